@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/factory"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interface/presenter"
 )
 
 func newBuildCmdFlag() *buildCmdFlag {
@@ -21,6 +25,102 @@ type buildCmdFlag struct {
 	CITool     string `mapstructure:"ciTool"`
 	WorkflowID string `mapstructure:"workflowID"`
 	Once       bool   `mapstructure:"once"`
+
+	GlobalTriggers   []string `mapstructure:"globalTriggers"`
+	MaxParallel      int      `mapstructure:"maxParallel"`
+	Retries          int      `mapstructure:"retries"`
+	KeepGoing        bool     `mapstructure:"keepGoing"`
+	ErrorOnNoChanges bool     `mapstructure:"errorOnNoChanges"`
+	DryRun           bool     `mapstructure:"dryRun"`
+	FailureLogLines  int      `mapstructure:"failureLogLines"`
+	Output           string   `mapstructure:"output"`
+	Reports          []string `mapstructure:"reports"`
+	CommitStatus     string   `mapstructure:"commitStatus"`
+	Dispatch         string   `mapstructure:"dispatch"`
+	DispatchWorkflow string   `mapstructure:"dispatchWorkflow"`
+	LookbackRuns     int      `mapstructure:"lookbackRuns"`
+	SuccessEvent     string   `mapstructure:"successEvent"`
+
+	ConfigFile   string         `mapstructure:"config"`
+	Timeout      time.Duration  `mapstructure:"timeout"`
+	PollInterval time.Duration  `mapstructure:"pollInterval"`
+	DispatchWait time.Duration  `mapstructure:"dispatchWait"`
+	Projects     []*projectFlag `mapstructure:"projects"`
+	Outputs      []*outputFlag  `mapstructure:"outputs"`
+	// additional inputs of dispatched workflows, only from a config file
+	DispatchInputs map[string]interface{} `mapstructure:"dispatchInputs"`
+}
+
+// outputFlag enables an additional output of builds, only from a config file
+type outputFlag struct {
+	Kind     string `mapstructure:"kind"`
+	File     string `mapstructure:"file"`
+	URL      string `mapstructure:"url"`
+	Format   string `mapstructure:"format"`
+	Threaded bool   `mapstructure:"threaded"`
+}
+
+// projectFlag overrides build settings for a project, only from a config file
+type projectFlag struct {
+	Name         string        `mapstructure:"name"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	PollInterval time.Duration `mapstructure:"pollInterval"`
+}
+
+func (f *buildCmdFlag) projectConfigs() map[string]factory.ProjectConfig {
+	configs := make(map[string]factory.ProjectConfig, len(f.Projects))
+	for _, p := range f.Projects {
+		configs[p.Name] = factory.ProjectConfig{
+			Timeout:      p.Timeout,
+			PollInterval: p.PollInterval,
+		}
+	}
+	return configs
+}
+
+// report kinds supported by --report
+const (
+	reportJUnit = "junit"
+)
+
+// reportFiles parses reports given as "<kind>=<file>", e.g., "junit=report.xml", into files by kind
+func (f *buildCmdFlag) reportFiles() (map[string]string, error) {
+	files := make(map[string]string, len(f.Reports))
+	for _, v := range f.Reports {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, errors.Errorf(`report "%s" must be in the form of "<kind>=<file>"`, v)
+		}
+		if file, ok := files[kv[0]]; ok {
+			return nil, errors.Errorf(`report "%s" is given twice, for "%s" and "%s"`, kv[0], file, kv[1])
+		}
+		switch kv[0] {
+		case reportJUnit:
+			files[kv[0]] = kv[1]
+		default:
+			return nil, errors.Errorf(`unknown report kind "%s" in "%s"`, kv[0], v)
+		}
+	}
+	return files, nil
+}
+
+// outputConfigs returns outputs from a config file, followed by reports
+func (f *buildCmdFlag) outputConfigs() []factory.OutputConfig {
+	configs := make([]factory.OutputConfig, 0, len(f.Outputs)+len(f.Reports))
+	for _, v := range f.Outputs {
+		configs = append(configs, factory.OutputConfig{
+			Kind:     v.Kind,
+			File:     v.File,
+			URL:      v.URL,
+			Format:   v.Format,
+			Threaded: v.Threaded,
+		})
+	}
+	reportFiles, _ := f.reportFiles()
+	if file, ok := reportFiles[reportJUnit]; ok {
+		configs = append(configs, factory.OutputConfig{Kind: factory.OutputJUnit, File: file})
+	}
+	return configs
 }
 
 func (f *buildCmdFlag) validate() error {
@@ -38,6 +138,46 @@ func (f *buildCmdFlag) validate() error {
 		joined := strings.Join(missing, ", ")
 		return errors.Errorf("required flags(s) %s not set", joined)
 	}
+	if _, err := presenter.ParseFormat(f.Output, presenter.Formats); err != nil {
+		return errors.Wrap(err, `invalid "OUTPUT"`)
+	}
+	if _, err := f.reportFiles(); err != nil {
+		return errors.Wrap(err, `invalid "REPORTS"`)
+	}
+	if f.MaxParallel < 0 {
+		return errors.Errorf(`"MAX_PARALLEL" must not be negative, got %d`, f.MaxParallel)
+	}
+	if f.Retries < 0 {
+		return errors.Errorf(`"RETRIES" must not be negative, got %d`, f.Retries)
+	}
+	if f.LookbackRuns < 0 {
+		return errors.Errorf(`"LOOKBACK_RUNS" must not be negative, got %d`, f.LookbackRuns)
+	}
+	if f.FailureLogLines < 0 {
+		return errors.Errorf(`"FAILURE_LOG_LINES" must not be negative, got %d`, f.FailureLogLines)
+	}
+	if f.Timeout <= 0 {
+		return errors.Errorf(`"BUILD_TIMEOUT" must be positive, got %s`, f.Timeout)
+	}
+	if f.PollInterval <= 0 {
+		return errors.Errorf(`"POLL_INTERVAL" must be positive, got %s`, f.PollInterval)
+	}
+	if f.DispatchWait <= 0 {
+		return errors.Errorf(`"DISPATCH_WAIT" must be positive, got %s`, f.DispatchWait)
+	}
+	for i, p := range f.Projects {
+		if p.Name == "" {
+			return errors.Errorf(`project %d in config file has no name`, i)
+		}
+		if p.Timeout < 0 || p.PollInterval < 0 {
+			return errors.Errorf(`timeout and poll interval of project "%s" must not be negative`, p.Name)
+		}
+	}
+	for i, o := range f.Outputs {
+		if o.Kind == "" {
+			return errors.Errorf(`output %d in config file has no kind`, i)
+		}
+	}
 	return nil
 }
 
@@ -54,27 +194,55 @@ func (b *commandsBuilder) newBuildCmd() *baseCmd {
 		Long:  "Trigger build workflow for projects that have changes",
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			err := readConfigFile(buildCmdViper)
+			if err != nil {
+				erWithCode(exitCodeConfigError, err)
+			}
 			f := newBuildCmdFlag()
-			err := f.validate()
+			err = f.validate()
 			if err != nil {
-				er(errors.Wrap(err, "fail to validate flags for list command"))
+				erWithCode(exitCodeConfigError, errors.Wrap(err, "fail to validate flags for build command"))
 			}
 
 			workDir, err := os.Getwd()
 			if err != nil {
 				er(errors.Wrap(err, "can't get current directory"))
 			}
-			ctrl, err := ciControllerFactory.New(workDir, f.CITool)
+			ctrl, err := ciControllerFactory.New(workDir, f.CITool, factory.Config{
+				GlobalTriggers:   f.GlobalTriggers,
+				MaxParallel:      f.MaxParallel,
+				Retries:          f.Retries,
+				KeepGoing:        f.KeepGoing,
+				ErrorOnNoChanges: f.ErrorOnNoChanges,
+				DryRun:           f.DryRun,
+				FailureLogLines:  f.FailureLogLines,
+				Timeout:          f.Timeout,
+				PollInterval:     f.PollInterval,
+				Projects:         f.projectConfigs(),
+				Output:           presenter.Format(f.Output),
+				Outputs:          f.outputConfigs(),
+				CommitStatus:     f.CommitStatus,
+				Dispatch:         f.Dispatch,
+				DispatchWorkflow: f.DispatchWorkflow,
+				DispatchInputs:   f.DispatchInputs,
+				DispatchWait:     f.DispatchWait,
+				LookbackRuns:     f.LookbackRuns,
+				SuccessEvent:     f.SuccessEvent,
+			})
 
 			if err != nil {
-				er(errors.Wrap(err, "can't create CI controller"))
+				erWithCode(exitCodeConfigError, errors.Wrap(err, "can't create CI controller"))
 			}
 
-			ctx := context.Background()
+			ctx, cancel := contextWithSignals(context.Background())
+			defer cancel()
 			if f.Once == true {
-				ctrl.BuildOnce(ctx, args, f.WorkflowID)
+				err = ctrl.BuildOnce(ctx, args, f.WorkflowID)
 			} else {
-				ctrl.Build(ctx, args, f.WorkflowID)
+				err = ctrl.Build(ctx, args, f.WorkflowID)
+			}
+			if err != nil {
+				erWithCode(exitCodeOf(err), err)
 			}
 		},
 	}
@@ -86,8 +254,80 @@ func (b *commandsBuilder) newBuildCmd() *baseCmd {
 	buildCmdViper.BindEnv("ciTool", "CI_TOOL")
 	buildCmdViper.BindEnv("workflowID", "WORKFLOW_ID")
 
+	buildCmd.PersistentFlags().StringSlice("global-trigger", nil, `glob of files that mark all projects as changed, e.g., "go.mod" or ".github/workflows/**"`)
+	buildCmdViper.BindPFlag("globalTriggers", buildCmd.PersistentFlags().Lookup("global-trigger"))
+	buildCmdViper.BindEnv("globalTriggers", "GLOBAL_TRIGGERS")
+
+	buildCmd.PersistentFlags().StringP("output", "o", string(presenter.FormatText), `output format, one of "text", "json" or "yaml"`)
+	buildCmdViper.BindPFlag("output", buildCmd.PersistentFlags().Lookup("output"))
+	buildCmdViper.BindEnv("output", "OUTPUT")
+
 	buildCmd.Flags().Bool("once", false, `join projects into single project and trigger only one workflow (default false)`)
 	buildCmdViper.BindPFlag("once", buildCmd.Flags().Lookup("once"))
 
+	buildCmd.Flags().Int("max-parallel", 0, `maximum number of builds running at the same time, 0 means unlimited`)
+	buildCmdViper.BindPFlag("maxParallel", buildCmd.Flags().Lookup("max-parallel"))
+	buildCmdViper.BindEnv("maxParallel", "MAX_PARALLEL")
+
+	buildCmd.Flags().Int("retries", 0, `number of times a failed build is re-triggered before it is considered failed`)
+	buildCmdViper.BindPFlag("retries", buildCmd.Flags().Lookup("retries"))
+	buildCmdViper.BindEnv("retries", "RETRIES")
+
+	buildCmd.Flags().Bool("keep-going", false, `wait for all builds even if some have failed, and report a summary, otherwise not finished builds are killed (default false)`)
+	buildCmdViper.BindPFlag("keepGoing", buildCmd.Flags().Lookup("keep-going"))
+	buildCmdViper.BindEnv("keepGoing", "KEEP_GOING")
+
+	buildCmd.Flags().Bool("error-on-no-changes", false, fmt.Sprintf(`exit with code %d when no project has changes (default false)`, exitCodeNoChanges))
+	buildCmdViper.BindPFlag("errorOnNoChanges", buildCmd.Flags().Lookup("error-on-no-changes"))
+	buildCmdViper.BindEnv("errorOnNoChanges", "ERROR_ON_NO_CHANGES")
+
+	buildCmd.Flags().Bool("dry-run", false, `print the builds that would be triggered, without triggering them (default false)`)
+	buildCmdViper.BindPFlag("dryRun", buildCmd.Flags().Lookup("dry-run"))
+	buildCmdViper.BindEnv("dryRun", "DRY_RUN")
+
+	buildCmd.Flags().Int("failure-log-lines", 20, `number of log lines of failed steps printed for failed builds, 0 disables it`)
+	buildCmdViper.BindPFlag("failureLogLines", buildCmd.Flags().Lookup("failure-log-lines"))
+	buildCmdViper.BindEnv("failureLogLines", "FAILURE_LOG_LINES")
+
+	buildCmd.Flags().Duration("timeout", 15*time.Minute, `maximum time to wait for builds, e.g., "30m"`)
+	buildCmdViper.BindPFlag("timeout", buildCmd.Flags().Lookup("timeout"))
+	buildCmdViper.BindEnv("timeout", "BUILD_TIMEOUT")
+
+	buildCmd.Flags().Duration("poll-interval", 15*time.Second, `interval between build status checks, e.g., "30s"`)
+	buildCmdViper.BindPFlag("pollInterval", buildCmd.Flags().Lookup("poll-interval"))
+	buildCmdViper.BindEnv("pollInterval", "POLL_INTERVAL")
+
+	buildCmd.Flags().StringSlice("report", nil, `write a report of builds as "<kind>=<file>", e.g., "junit=report.xml"`)
+	buildCmdViper.BindPFlag("reports", buildCmd.Flags().Lookup("report"))
+	buildCmdViper.BindEnv("reports", "REPORTS")
+
+	buildCmd.Flags().String("commit-status", "", `report the status of each project on the current commit as "monorepo/<project>", "status" or "check-run" for github`)
+	buildCmdViper.BindPFlag("commitStatus", buildCmd.Flags().Lookup("commit-status"))
+	buildCmdViper.BindEnv("commitStatus", "COMMIT_STATUS")
+
+	buildCmd.Flags().String("dispatch", "repository", `how builds are dispatched, "repository" or "workflow" for github`)
+	buildCmdViper.BindPFlag("dispatch", buildCmd.Flags().Lookup("dispatch"))
+	buildCmdViper.BindEnv("dispatch", "DISPATCH")
+
+	buildCmd.Flags().String("dispatch-workflow", "build-{project}.yml", `workflow file dispatched for each project with "--dispatch workflow", "{project}" is replaced by a project name`)
+	buildCmdViper.BindPFlag("dispatchWorkflow", buildCmd.Flags().Lookup("dispatch-workflow"))
+	buildCmdViper.BindEnv("dispatchWorkflow", "DISPATCH_WORKFLOW")
+
+	buildCmd.Flags().Duration("dispatch-wait", time.Minute, `maximum time to wait for the run created by a dispatched build, e.g., "2m"`)
+	buildCmdViper.BindPFlag("dispatchWait", buildCmd.Flags().Lookup("dispatch-wait"))
+	buildCmdViper.BindEnv("dispatchWait", "DISPATCH_WAIT")
+
+	buildCmd.Flags().Int("lookback-runs", 300, `maximum number of successful builds looked back for the last successful commit`)
+	buildCmdViper.BindPFlag("lookbackRuns", buildCmd.Flags().Lookup("lookback-runs"))
+	buildCmdViper.BindEnv("lookbackRuns", "LOOKBACK_RUNS")
+
+	buildCmd.Flags().String("success-event", "", `event of builds considered for the last successful commit, e.g., "push", any event if empty`)
+	buildCmdViper.BindPFlag("successEvent", buildCmd.Flags().Lookup("success-event"))
+	buildCmdViper.BindEnv("successEvent", "SUCCESS_EVENT")
+
+	buildCmd.Flags().String("config", defaultConfigFile, `config file with per-project settings, ignored if the default one does not exist`)
+	buildCmdViper.BindPFlag("config", buildCmd.Flags().Lookup("config"))
+	buildCmdViper.BindEnv("config", "CONFIG_FILE")
+
 	return &baseCmd{cmd: buildCmd}
 }
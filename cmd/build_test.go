@@ -3,12 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/spf13/cobra"
+	"github.com/whatthefar/monorepo-toolkit/pkg/factory"
 	factory_mock "github.com/whatthefar/monorepo-toolkit/pkg/factory/mock"
 	mock_controller "github.com/whatthefar/monorepo-toolkit/pkg/interface/controller/mock"
 )
@@ -28,6 +33,24 @@ func TestBuildCmdFlag(t *testing.T) {
 				tool       string
 				workflowID string
 				once       bool
+
+				globalTriggers []string
+				maxParallel    int
+				retries        int
+				keepGoing      bool
+
+				errorOnNoChanges bool
+				dryRun           bool
+				failureLogLines  *int
+
+				timeout      time.Duration
+				pollInterval time.Duration
+				output       string
+				reports      []string
+				commitStatus string
+				dispatch     string
+				workflowFile string
+				dispatchWait time.Duration
 			}{
 				{
 					args: []string{
@@ -53,15 +76,216 @@ func TestBuildCmdFlag(t *testing.T) {
 					workflowID: "main.yml",
 					once:       false,
 				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--global-trigger", "go.mod",
+						"--global-trigger", ".github/workflows/**",
+						"services",
+					},
+					tool:           "github",
+					workflowID:     "main.yml",
+					once:           false,
+					globalTriggers: []string{"go.mod", ".github/workflows/**"},
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--max-parallel", "4",
+						"services",
+					},
+					tool:        "github",
+					workflowID:  "main.yml",
+					once:        false,
+					maxParallel: 4,
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--retries", "2",
+						"services",
+					},
+					tool:       "github",
+					workflowID: "main.yml",
+					once:       false,
+					retries:    2,
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--keep-going",
+						"services",
+					},
+					tool:       "github",
+					workflowID: "main.yml",
+					once:       false,
+					keepGoing:  true,
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--error-on-no-changes",
+						"services",
+					},
+					tool:             "github",
+					workflowID:       "main.yml",
+					once:             false,
+					errorOnNoChanges: true,
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--dry-run",
+						"services",
+					},
+					tool:       "github",
+					workflowID: "main.yml",
+					once:       false,
+					dryRun:     true,
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--failure-log-lines", "0",
+						"services",
+					},
+					tool:            "github",
+					workflowID:      "main.yml",
+					once:            false,
+					failureLogLines: intAddr(0),
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--timeout", "1h",
+						"--poll-interval", "1m",
+						"services",
+					},
+					tool:         "github",
+					workflowID:   "main.yml",
+					once:         false,
+					timeout:      time.Hour,
+					pollInterval: time.Minute,
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"-o", "json",
+						"services",
+					},
+					tool:       "github",
+					workflowID: "main.yml",
+					once:       false,
+					output:     "json",
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--report", "junit=report.xml",
+						"services",
+					},
+					tool:       "github",
+					workflowID: "main.yml",
+					once:       false,
+					reports:    []string{"junit=report.xml"},
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--commit-status", "check-run",
+						"services",
+					},
+					tool:         "github",
+					workflowID:   "main.yml",
+					once:         false,
+					commitStatus: "check-run",
+				},
+				{
+					args: []string{
+						"build",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--dispatch", "workflow",
+						"--dispatch-workflow", "{project}.yml",
+						"--dispatch-wait", "2m",
+						"services",
+					},
+					tool:         "github",
+					workflowID:   "main.yml",
+					once:         false,
+					dispatch:     "workflow",
+					workflowFile: "{project}.yml",
+					dispatchWait: 2 * time.Minute,
+				},
 			}
 
 			for i, v := range cases {
 				var (
-					args       = v.args
-					tool       = v.tool
-					workflowID = v.workflowID
-					once       = v.once
+					args           = v.args
+					tool           = v.tool
+					workflowID     = v.workflowID
+					once           = v.once
+					globalTriggers = v.globalTriggers
+					maxParallel    = v.maxParallel
+					retries        = v.retries
+					keepGoing      = v.keepGoing
+
+					errorOnNoChanges = v.errorOnNoChanges
+					dryRun           = v.dryRun
+					failureLogLines  = 20
+
+					timeout      = v.timeout
+					pollInterval = v.pollInterval
+					output       = v.output
+					reports      = v.reports
+					commitStatus = v.commitStatus
+					dispatch     = v.dispatch
+					workflowFile = v.workflowFile
+					dispatchWait = v.dispatchWait
 				)
+				if v.failureLogLines != nil {
+					failureLogLines = *v.failureLogLines
+				}
+				if timeout == 0 {
+					timeout = 15 * time.Minute
+				}
+				if pollInterval == 0 {
+					pollInterval = 15 * time.Second
+				}
+				if output == "" {
+					output = "text"
+				}
+				if dispatch == "" {
+					dispatch = "repository"
+				}
+				if workflowFile == "" {
+					workflowFile = "build-{project}.yml"
+				}
+				if dispatchWait == 0 {
+					dispatchWait = time.Minute
+				}
 
 				Convey(fmt.Sprintf("Case %d, when execute cmd with flags", i), func() {
 					cmd.SetArgs(args)
@@ -72,6 +296,29 @@ func TestBuildCmdFlag(t *testing.T) {
 						So(flags.CITool, ShouldEqual, tool)
 						So(flags.WorkflowID, ShouldEqual, workflowID)
 						So(flags.Once, ShouldEqual, once)
+						if globalTriggers == nil {
+							So(flags.GlobalTriggers, ShouldBeEmpty)
+						} else {
+							So(flags.GlobalTriggers, ShouldResemble, globalTriggers)
+						}
+						So(flags.MaxParallel, ShouldEqual, maxParallel)
+						So(flags.Retries, ShouldEqual, retries)
+						So(flags.KeepGoing, ShouldEqual, keepGoing)
+						So(flags.ErrorOnNoChanges, ShouldEqual, errorOnNoChanges)
+						So(flags.DryRun, ShouldEqual, dryRun)
+						So(flags.FailureLogLines, ShouldEqual, failureLogLines)
+						So(flags.Timeout, ShouldEqual, timeout)
+						So(flags.PollInterval, ShouldEqual, pollInterval)
+						So(flags.Output, ShouldEqual, output)
+						if reports == nil {
+							So(flags.Reports, ShouldBeEmpty)
+						} else {
+							So(flags.Reports, ShouldResemble, reports)
+						}
+						So(flags.CommitStatus, ShouldEqual, commitStatus)
+						So(flags.Dispatch, ShouldEqual, dispatch)
+						So(flags.DispatchWorkflow, ShouldEqual, workflowFile)
+						So(flags.DispatchWait, ShouldEqual, dispatchWait)
 					})
 				})
 			}
@@ -79,11 +326,136 @@ func TestBuildCmdFlag(t *testing.T) {
 	})
 }
 
+func TestBuildCmdConfigFile(t *testing.T) {
+	Convey("Given a monorepo-toolkit command", t, func() {
+		cmd := newMonorepoToolkit()
+		Convey("Given a config file with per-project settings", func() {
+			dir, err := ioutil.TempDir("", "monorepo-toolkit")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			configFile := filepath.Join(dir, "config.yml")
+			content := `
+pollInterval: 30s
+projects:
+  - name: App1
+    timeout: 1h
+  - name: app2
+    pollInterval: 1m
+outputs:
+  - kind: json
+    file: events.jsonl
+  - kind: webhook
+    url: $SLACK_WEBHOOK_URL
+    format: slack
+    threaded: true
+dispatchInputs:
+  environment: staging
+  debug: true
+  replicas: 2
+`
+			So(ioutil.WriteFile(configFile, []byte(content), 0644), ShouldBeNil)
+
+			var flags *buildCmdFlag
+			var readErr error
+			buildCmd.Run = func(cmd *cobra.Command, args []string) {
+				readErr = readConfigFile(buildCmdViper)
+				flags = newBuildCmdFlag()
+			}
+
+			Convey("When execute cmd with the config file", func() {
+				cmd.SetArgs([]string{
+					"build",
+					"--ci-tool", "github",
+					"--workflow", "main.yml",
+					"--timeout", "20m",
+					"--report", "junit=report.xml",
+					"--config", configFile,
+					"services",
+				})
+				err := cmd.Execute()
+
+				Convey("It should merge the config file with flags", func() {
+					So(err, ShouldBeNil)
+					So(readErr, ShouldBeNil)
+					So(flags.Timeout, ShouldEqual, 20*time.Minute)
+					So(flags.PollInterval, ShouldEqual, 30*time.Second)
+					So(flags.validate(), ShouldBeNil)
+					So(flags.projectConfigs(), ShouldResemble, map[string]factory.ProjectConfig{
+						"App1": {Timeout: time.Hour},
+						"app2": {PollInterval: time.Minute},
+					})
+					So(flags.outputConfigs(), ShouldResemble, []factory.OutputConfig{
+						{Kind: "json", File: "events.jsonl"},
+						{Kind: "webhook", URL: "$SLACK_WEBHOOK_URL", Format: "slack", Threaded: true},
+						{Kind: "junit", File: "report.xml"},
+					})
+					So(flags.DispatchInputs, ShouldResemble, map[string]interface{}{
+						"environment": "staging",
+						"debug":       true,
+						"replicas":    2,
+					})
+				})
+			})
+
+			Convey("When execute cmd with a missing config file", func() {
+				cmd.SetArgs([]string{
+					"build",
+					"--ci-tool", "github",
+					"--workflow", "main.yml",
+					"--config", filepath.Join(dir, "missing.yml"),
+					"services",
+				})
+				err := cmd.Execute()
+
+				Convey("It should fail to read the config file", func() {
+					So(err, ShouldBeNil)
+					So(readErr, ShouldNotBeNil)
+				})
+			})
+		})
+	})
+}
+
+func TestBuildCmdFlag_reportFiles(t *testing.T) {
+	Convey("Given reports of build flags", t, func() {
+		cases := []*struct {
+			reports []string
+			files   map[string]string
+			isErr   bool
+		}{
+			{reports: nil, files: map[string]string{}},
+			{reports: []string{"junit=out/report.xml"}, files: map[string]string{"junit": "out/report.xml"}},
+			{reports: []string{"junit"}, isErr: true},
+			{reports: []string{"junit="}, isErr: true},
+			{reports: []string{"html=report.html"}, isErr: true},
+			{reports: []string{"junit=a.xml", "junit=b.xml"}, isErr: true},
+		}
+
+		for i, v := range cases {
+			c := v
+			Convey(fmt.Sprintf("Case %d, when parses %v", i, c.reports), func() {
+				f := &buildCmdFlag{Reports: c.reports}
+				files, err := f.reportFiles()
+
+				Convey("It should return files by kind or an error", func() {
+					if c.isErr {
+						So(err, ShouldBeError)
+					} else {
+						So(err, ShouldBeNil)
+						So(files, ShouldResemble, c.files)
+					}
+				})
+			})
+		}
+	})
+}
+
 func TestBuildProjectsCmd(t *testing.T) {
 	Convey("Given a monorepo-toolkit command", t, func() {
 		cmd := newMonorepoToolkit()
 		Convey("Given a mock CI controller factory", func() {
-			ctx := context.Background()
+			// the context is cancellable by signals
+			ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
 			ctrl := gomock.NewController(t)
 			ciContoller := mock_controller.NewMockCI(ctrl)
 			factory := factory_mock.NewMockCIControllerFactory(ctrl)
@@ -106,7 +478,7 @@ func TestBuildProjectsCmd(t *testing.T) {
 					tool: "github",
 					expect: func() {
 						ciContoller.EXPECT().
-							BuildOnce(ctx, []string{"services"}, "main.yml")
+							BuildOnce(gomock.AssignableToTypeOf(ctxType), []string{"services"}, "main.yml")
 					},
 				},
 				{
@@ -120,7 +492,7 @@ func TestBuildProjectsCmd(t *testing.T) {
 					tool: "github",
 					expect: func() {
 						ciContoller.EXPECT().
-							Build(ctx, []string{"services"}, "main.yml")
+							Build(gomock.AssignableToTypeOf(ctxType), []string{"services"}, "main.yml")
 					},
 				},
 			}
@@ -135,7 +507,7 @@ func TestBuildProjectsCmd(t *testing.T) {
 				Convey(fmt.Sprintf("Case %d, given ci controller and factory are mocked", i), func() {
 					wd, err := os.Getwd()
 					So(err, ShouldBeNil)
-					factory.EXPECT().New(wd, tool).Return(ciContoller, nil)
+					factory.EXPECT().New(wd, tool, gomock.Any()).Return(ciContoller, nil)
 					expect()
 
 					Convey("When execute cmd with args", func() {
@@ -154,3 +526,7 @@ func TestBuildProjectsCmd(t *testing.T) {
 		})
 	})
 }
+
+func intAddr(i int) *int {
+	return &i
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/factory"
+)
+
+func newCommentCmdFlag() *commentCmdFlag {
+	f := &commentCmdFlag{}
+	commentCmdViper.Unmarshal(f)
+	return f
+}
+
+type commentCmdFlag struct {
+	CITool     string `mapstructure:"ciTool"`
+	WorkflowID string `mapstructure:"workflowID"`
+
+	GlobalTriggers   []string `mapstructure:"globalTriggers"`
+	LookbackRuns     int      `mapstructure:"lookbackRuns"`
+	SuccessEvent     string   `mapstructure:"successEvent"`
+	Dispatch         string   `mapstructure:"dispatch"`
+	DispatchWorkflow string   `mapstructure:"dispatchWorkflow"`
+}
+
+func (f *commentCmdFlag) validate() error {
+	missing := make([]string, 0)
+	if f.CITool == "" {
+		missing = append(missing, "CI_TOOL")
+	}
+	if f.WorkflowID == "" {
+		missing = append(missing, "WORKFLOW_ID")
+	}
+	if len(missing) > 0 {
+		for i, v := range missing {
+			missing[i] = fmt.Sprintf(`"%s"`, v)
+		}
+		joined := strings.Join(missing, ", ")
+		return errors.Errorf("required flags(s) %s not set", joined)
+	}
+	if f.LookbackRuns < 0 {
+		return errors.Errorf(`"LOOKBACK_RUNS" must not be negative, got %d`, f.LookbackRuns)
+	}
+	return nil
+}
+
+var (
+	commentCmd *cobra.Command
+
+	commentCmdViper = viper.New()
+)
+
+func (b *commandsBuilder) newCommentCmd() *baseCmd {
+	commentCmd = &cobra.Command{
+		Use:   "comment [paths]",
+		Short: "Commenting projects that have changes on the pull request",
+		Long: `Commenting projects that have changes since the last successful build on the pull request being built,
+why each is affected, and links to their builds.
+
+Links to builds are found as the build command dispatches them, so give the same "--dispatch" flags.
+Only builds dispatched by "--dispatch workflow" on the branch of the pull request are linked.
+The comment is updated in place by later runs, e.g., run it again after builds to fill in links to them.
+Nothing is commented if not built for a pull request.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := readConfigFile(commentCmdViper)
+			if err != nil {
+				erWithCode(exitCodeConfigError, err)
+			}
+			f := newCommentCmdFlag()
+			err = f.validate()
+			if err != nil {
+				erWithCode(exitCodeConfigError, errors.Wrap(err, "fail to validate flags for comment command"))
+			}
+
+			workDir, err := os.Getwd()
+			if err != nil {
+				er(errors.Wrap(err, "can't get current directory"))
+			}
+			ctrl, err := ciControllerFactory.New(workDir, f.CITool, factory.Config{
+				GlobalTriggers:   f.GlobalTriggers,
+				LookbackRuns:     f.LookbackRuns,
+				SuccessEvent:     f.SuccessEvent,
+				Dispatch:         f.Dispatch,
+				DispatchWorkflow: f.DispatchWorkflow,
+			})
+			if err != nil {
+				erWithCode(exitCodeConfigError, errors.Wrap(err, "can't create CI controller"))
+			}
+
+			_, err = ctrl.CommentProjects(context.Background(), args, f.WorkflowID)
+			if err != nil {
+				erWithCode(exitCodeOf(err), err)
+			}
+		},
+	}
+
+	commentCmd.Flags().StringP("ci-tool", "C", "", `CI provider, e.g., "github"`)
+	commentCmd.Flags().StringP("workflow", "W", "", "Workflow ID, e.g., a file name for github action")
+	commentCmdViper.BindPFlag("ciTool", commentCmd.Flags().Lookup("ci-tool"))
+	commentCmdViper.BindPFlag("workflowID", commentCmd.Flags().Lookup("workflow"))
+	commentCmdViper.BindEnv("ciTool", "CI_TOOL")
+	commentCmdViper.BindEnv("workflowID", "WORKFLOW_ID")
+
+	commentCmd.Flags().StringSlice("global-trigger", nil, `glob of files that mark all projects as changed, e.g., "go.mod" or ".github/workflows/**"`)
+	commentCmdViper.BindPFlag("globalTriggers", commentCmd.Flags().Lookup("global-trigger"))
+	commentCmdViper.BindEnv("globalTriggers", "GLOBAL_TRIGGERS")
+
+	commentCmd.Flags().Int("lookback-runs", 300, `maximum number of successful builds looked back for the last successful commit`)
+	commentCmdViper.BindPFlag("lookbackRuns", commentCmd.Flags().Lookup("lookback-runs"))
+	commentCmdViper.BindEnv("lookbackRuns", "LOOKBACK_RUNS")
+
+	commentCmd.Flags().String("success-event", "", `event of builds considered for the last successful commit, e.g., "push", any event if empty`)
+	commentCmdViper.BindPFlag("successEvent", commentCmd.Flags().Lookup("success-event"))
+	commentCmdViper.BindEnv("successEvent", "SUCCESS_EVENT")
+
+	commentCmd.Flags().String("dispatch", "repository", `how builds are dispatched by the build command, to find links to them`)
+	commentCmdViper.BindPFlag("dispatch", commentCmd.Flags().Lookup("dispatch"))
+	commentCmdViper.BindEnv("dispatch", "DISPATCH")
+
+	commentCmd.Flags().String("dispatch-workflow", "build-{project}.yml", `workflow file dispatched for each project with "--dispatch workflow"`)
+	commentCmdViper.BindPFlag("dispatchWorkflow", commentCmd.Flags().Lookup("dispatch-workflow"))
+	commentCmdViper.BindEnv("dispatchWorkflow", "DISPATCH_WORKFLOW")
+
+	commentCmd.Flags().String("config", defaultConfigFile, `config file shared with the build command, e.g., for global triggers, ignored if the default one does not exist`)
+	commentCmdViper.BindPFlag("config", commentCmd.Flags().Lookup("config"))
+	commentCmdViper.BindEnv("config", "CONFIG_FILE")
+
+	return &baseCmd{cmd: commentCmd}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/cobra"
+
+	factory_mock "github.com/whatthefar/monorepo-toolkit/pkg/factory/mock"
+	mock_controller "github.com/whatthefar/monorepo-toolkit/pkg/interface/controller/mock"
+)
+
+func TestCommentCmdFlag(t *testing.T) {
+	Convey("Given a monorepo-toolkit command", t, func() {
+		cmd := newMonorepoToolkit()
+		Convey("Given a run func is mocked", func() {
+			var flags *commentCmdFlag
+			commentCmd.Run = func(cmd *cobra.Command, args []string) {
+				flags = newCommentCmdFlag()
+			}
+
+			Convey("When execute cmd with flags", func() {
+				cmd.SetArgs([]string{
+					"comment",
+					"--ci-tool", "github",
+					"--workflow", "main.yml",
+					"--global-trigger", "go.mod",
+					"--dispatch", "workflow",
+					"services",
+				})
+				err := cmd.Execute()
+
+				Convey("It should unmarhsal flags", func() {
+					So(err, ShouldBeNil)
+					So(flags.CITool, ShouldEqual, "github")
+					So(flags.WorkflowID, ShouldEqual, "main.yml")
+					So(flags.GlobalTriggers, ShouldResemble, []string{"go.mod"})
+					So(flags.Dispatch, ShouldEqual, "workflow")
+					So(flags.DispatchWorkflow, ShouldEqual, "build-{project}.yml")
+				})
+			})
+		})
+
+		Convey("Given a config file shared with the build command", func() {
+			dir, err := ioutil.TempDir("", "monorepo-toolkit")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			configFile := filepath.Join(dir, "config.yml")
+			content := `
+globalTriggers:
+  - go.mod
+timeout: 30m
+`
+			So(ioutil.WriteFile(configFile, []byte(content), 0644), ShouldBeNil)
+
+			var flags *commentCmdFlag
+			var readErr error
+			commentCmd.Run = func(cmd *cobra.Command, args []string) {
+				readErr = readConfigFile(commentCmdViper)
+				flags = newCommentCmdFlag()
+			}
+
+			Convey("When execute cmd with the config file", func() {
+				cmd.SetArgs([]string{
+					"comment",
+					"--ci-tool", "github",
+					"--workflow", "main.yml",
+					"--config", configFile,
+					"services",
+				})
+				err := cmd.Execute()
+
+				Convey("It should read global triggers from the config file", func() {
+					So(err, ShouldBeNil)
+					So(readErr, ShouldBeNil)
+					So(flags.GlobalTriggers, ShouldResemble, []string{"go.mod"})
+				})
+			})
+		})
+	})
+}
+
+func TestCommentCmd(t *testing.T) {
+	Convey("Given a monorepo-toolkit command", t, func() {
+		cmd := newMonorepoToolkit()
+		Convey("Given ci controller and factory are mocked", func() {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			ciContoller := mock_controller.NewMockCI(ctrl)
+			factory := factory_mock.NewMockCIControllerFactory(ctrl)
+			// replace default factory with the mock one
+			ciControllerFactory = factory
+
+			wd, err := os.Getwd()
+			So(err, ShouldBeNil)
+			factory.EXPECT().New(wd, "github", gomock.Any()).Return(ciContoller, nil)
+			ciContoller.EXPECT().
+				CommentProjects(ctx, []string{"services"}, "main.yml")
+
+			Convey("When execute cmd with args", func() {
+				cmd.SetArgs([]string{
+					"comment",
+					"--ci-tool", "github",
+					"--workflow", "main.yml",
+					"services",
+				})
+				err = cmd.Execute()
+
+				Convey("It should comment projects", func() {
+					So(err, ShouldBeNil)
+					ctrl.Finish()
+				})
+			})
+		})
+	})
+}
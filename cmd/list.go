@@ -9,6 +9,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/factory"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interface/presenter"
 )
 
 func newListCmdFlag() *listCmdFlag {
@@ -20,6 +23,11 @@ func newListCmdFlag() *listCmdFlag {
 type listCmdFlag struct {
 	CITool     string `mapstructure:"ciTool"`
 	WorkflowID string `mapstructure:"workflowID"`
+
+	GlobalTriggers []string `mapstructure:"globalTriggers"`
+	Output         string   `mapstructure:"output"`
+	LookbackRuns   int      `mapstructure:"lookbackRuns"`
+	SuccessEvent   string   `mapstructure:"successEvent"`
 }
 
 func newListProjectsCmdFlag() *listProjectsCmdFlag {
@@ -48,6 +56,12 @@ func (f *listCmdFlag) validate() error {
 		joined := strings.Join(missing, ", ")
 		return errors.Errorf("required flags(s) %s not set", joined)
 	}
+	if _, err := presenter.ParseFormat(f.Output, presenter.ListFormats); err != nil {
+		return errors.Wrap(err, `invalid "OUTPUT"`)
+	}
+	if f.LookbackRuns < 0 {
+		return errors.Errorf(`"LOOKBACK_RUNS" must not be negative, got %d`, f.LookbackRuns)
+	}
 	return nil
 }
 
@@ -73,26 +87,38 @@ List requires a subcommand, e.g., ` + "`monorepo-toolkit list projects`",
 		Long:  `Listing projects that have changes since the last successful build`,
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			err := readConfigFile(listCmdViper)
+			if err != nil {
+				erWithCode(exitCodeConfigError, err)
+			}
 			f := newListProjectsCmdFlag()
-			err := f.validate()
+			err = f.validate()
 			if err != nil {
-				er(errors.Wrap(err, "fail to validate flags for list command"))
+				erWithCode(exitCodeConfigError, errors.Wrap(err, "fail to validate flags for list command"))
 			}
 
 			workDir, err := os.Getwd()
 			if err != nil {
 				er(errors.Wrap(err, "can't get current directory"))
 			}
-			ctrl, err := ciControllerFactory.New(workDir, f.CITool)
+			ctrl, err := ciControllerFactory.New(workDir, f.CITool, factory.Config{
+				GlobalTriggers: f.GlobalTriggers,
+				Output:         presenter.Format(f.Output),
+				LookbackRuns:   f.LookbackRuns,
+				SuccessEvent:   f.SuccessEvent,
+			})
 			if err != nil {
-				er(errors.Wrap(err, "can't create CI controller"))
+				erWithCode(exitCodeConfigError, errors.Wrap(err, "can't create CI controller"))
 			}
 
 			ctx := context.Background()
 			if f.Join == true {
-				ctrl.ListProjectsJoined(ctx, args, f.WorkflowID)
+				_, err = ctrl.ListProjectsJoined(ctx, args, f.WorkflowID)
 			} else {
-				ctrl.ListProjects(ctx, args, f.WorkflowID)
+				_, err = ctrl.ListProjects(ctx, args, f.WorkflowID)
+			}
+			if err != nil {
+				erWithCode(exitCodeOf(err), err)
 			}
 		},
 	}
@@ -104,6 +130,26 @@ List requires a subcommand, e.g., ` + "`monorepo-toolkit list projects`",
 	listCmdViper.BindEnv("ciTool", "CI_TOOL")
 	listCmdViper.BindEnv("workflowID", "WORKFLOW_ID")
 
+	listCmd.PersistentFlags().StringSlice("global-trigger", nil, `glob of files that mark all projects as changed, e.g., "go.mod" or ".github/workflows/**"`)
+	listCmdViper.BindPFlag("globalTriggers", listCmd.PersistentFlags().Lookup("global-trigger"))
+	listCmdViper.BindEnv("globalTriggers", "GLOBAL_TRIGGERS")
+
+	listCmd.PersistentFlags().StringP("output", "o", string(presenter.FormatText), `output format, one of "text", "json", "yaml" or "github-matrix"`)
+	listCmdViper.BindPFlag("output", listCmd.PersistentFlags().Lookup("output"))
+	listCmdViper.BindEnv("output", "OUTPUT")
+
+	listCmd.PersistentFlags().Int("lookback-runs", 300, `maximum number of successful builds looked back for the last successful commit`)
+	listCmdViper.BindPFlag("lookbackRuns", listCmd.PersistentFlags().Lookup("lookback-runs"))
+	listCmdViper.BindEnv("lookbackRuns", "LOOKBACK_RUNS")
+
+	listCmd.PersistentFlags().String("success-event", "", `event of builds considered for the last successful commit, e.g., "push", any event if empty`)
+	listCmdViper.BindPFlag("successEvent", listCmd.PersistentFlags().Lookup("success-event"))
+	listCmdViper.BindEnv("successEvent", "SUCCESS_EVENT")
+
+	listCmd.PersistentFlags().String("config", defaultConfigFile, `config file shared with the build command, e.g., for global triggers, ignored if the default one does not exist`)
+	listCmdViper.BindPFlag("config", listCmd.PersistentFlags().Lookup("config"))
+	listCmdViper.BindEnv("config", "CONFIG_FILE")
+
 	listProjectsCmd.Flags().Bool("join", false, `join projects into single project (default false)`)
 	listCmdViper.BindPFlag("join", listProjectsCmd.Flags().Lookup("join"))
 
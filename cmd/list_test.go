@@ -3,7 +3,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -29,6 +31,11 @@ func TestListProjectsCmdFlag(t *testing.T) {
 				tool       string
 				workflowID string
 				join       bool
+
+				globalTriggers []string
+				output         string
+				lookbackRuns   int
+				successEvent   string
 			}{
 				{
 					args: []string{
@@ -54,15 +61,80 @@ func TestListProjectsCmdFlag(t *testing.T) {
 					workflowID: "main.yml",
 					join:       false,
 				},
+				{
+					args: []string{
+						"list", "projects",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--global-trigger", "go.mod",
+						"--global-trigger", ".github/workflows/**",
+						"services",
+					},
+					tool:           "github",
+					workflowID:     "main.yml",
+					join:           false,
+					globalTriggers: []string{"go.mod", ".github/workflows/**"},
+				},
+				{
+					args: []string{
+						"list", "projects",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--output", "yaml",
+						"services",
+					},
+					tool:       "github",
+					workflowID: "main.yml",
+					join:       false,
+					output:     "yaml",
+				},
+				{
+					args: []string{
+						"list", "projects",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"-o", "github-matrix",
+						"services",
+					},
+					tool:       "github",
+					workflowID: "main.yml",
+					join:       false,
+					output:     "github-matrix",
+				},
+				{
+					args: []string{
+						"list", "projects",
+						"--ci-tool", "github",
+						"--workflow", "main.yml",
+						"--lookback-runs", "50",
+						"--success-event", "push",
+						"services",
+					},
+					tool:         "github",
+					workflowID:   "main.yml",
+					join:         false,
+					lookbackRuns: 50,
+					successEvent: "push",
+				},
 			}
 
 			for i, v := range cases {
 				var (
-					args       = v.args
-					tool       = v.tool
-					workflowID = v.workflowID
-					join       = v.join
+					args           = v.args
+					tool           = v.tool
+					workflowID     = v.workflowID
+					join           = v.join
+					globalTriggers = v.globalTriggers
+					output         = v.output
+					lookbackRuns   = v.lookbackRuns
+					successEvent   = v.successEvent
 				)
+				if output == "" {
+					output = "text"
+				}
+				if lookbackRuns == 0 {
+					lookbackRuns = 300
+				}
 
 				Convey(fmt.Sprintf("Case %d, when execute cmd with flags", i), func() {
 					cmd.SetArgs(args)
@@ -73,6 +145,14 @@ func TestListProjectsCmdFlag(t *testing.T) {
 						So(flags.CITool, ShouldEqual, tool)
 						So(flags.WorkflowID, ShouldEqual, workflowID)
 						So(flags.Join, ShouldEqual, join)
+						if globalTriggers == nil {
+							So(flags.GlobalTriggers, ShouldBeEmpty)
+						} else {
+							So(flags.GlobalTriggers, ShouldResemble, globalTriggers)
+						}
+						So(flags.Output, ShouldEqual, output)
+						So(flags.LookbackRuns, ShouldEqual, lookbackRuns)
+						So(flags.SuccessEvent, ShouldEqual, successEvent)
 					})
 				})
 			}
@@ -136,7 +216,7 @@ func TestListProjectsCmd(t *testing.T) {
 				Convey(fmt.Sprintf("Case %d, given ci controller and factory are mocked", i), func() {
 					wd, err := os.Getwd()
 					So(err, ShouldBeNil)
-					factory.EXPECT().New(wd, tool).Return(ciContoller, nil)
+					factory.EXPECT().New(wd, tool, gomock.Any()).Return(ciContoller, nil)
 					expect()
 
 					Convey("When execute cmd with args", func() {
@@ -155,3 +235,63 @@ func TestListProjectsCmd(t *testing.T) {
 		})
 	})
 }
+
+func TestListProjectsCmdConfigFile(t *testing.T) {
+	Convey("Given a monorepo-toolkit command", t, func() {
+		cmd := newMonorepoToolkit()
+		Convey("Given a config file shared with the build command", func() {
+			dir, err := ioutil.TempDir("", "monorepo-toolkit")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			configFile := filepath.Join(dir, "config.yml")
+			content := `
+globalTriggers:
+  - go.mod
+  - .github/workflows/**
+`
+			So(ioutil.WriteFile(configFile, []byte(content), 0644), ShouldBeNil)
+
+			var flags *listProjectsCmdFlag
+			var readErr error
+			listProjectsCmd.Run = func(cmd *cobra.Command, args []string) {
+				readErr = readConfigFile(listCmdViper)
+				flags = newListProjectsCmdFlag()
+			}
+
+			Convey("When execute cmd with the config file", func() {
+				cmd.SetArgs([]string{
+					"list", "projects",
+					"--ci-tool", "github",
+					"--workflow", "main.yml",
+					"--config", configFile,
+					"services",
+				})
+				err := cmd.Execute()
+
+				Convey("It should read global triggers from the config file", func() {
+					So(err, ShouldBeNil)
+					So(readErr, ShouldBeNil)
+					So(flags.GlobalTriggers, ShouldResemble, []string{"go.mod", ".github/workflows/**"})
+				})
+			})
+
+			Convey("When execute cmd with the config file, and global triggers by flags", func() {
+				cmd.SetArgs([]string{
+					"list", "projects",
+					"--ci-tool", "github",
+					"--workflow", "main.yml",
+					"--config", configFile,
+					"--global-trigger", "go.sum",
+					"services",
+				})
+				err := cmd.Execute()
+
+				Convey("It should take flags over the config file", func() {
+					So(err, ShouldBeNil)
+					So(readErr, ShouldBeNil)
+					So(flags.GlobalTriggers, ShouldResemble, []string{"go.sum"})
+				})
+			})
+		})
+	})
+}
@@ -37,6 +37,7 @@ func (b *commandsBuilder) addAll() *commandsBuilder {
 	b.addCommands(
 		b.newListCmd(),
 		b.newBuildCmd(),
+		b.newCommentCmd(),
 	)
 	return b
 }
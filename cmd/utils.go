@@ -1,17 +1,123 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 
 	"github.com/whatthefar/monorepo-toolkit/pkg/factory"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 )
 
 var (
 	ciControllerFactory = factory.CIController
 )
 
+// Exit codes, see "Exit codes" section in README.md
+const (
+	exitCodeError        = 1
+	exitCodeBuildFailed  = 2
+	exitCodeBuildTimeout = 3
+	exitCodeConfigError  = 4
+	exitCodeAPIError     = 5
+	exitCodeNoChanges    = 6
+	// same as a shell interrupted by SIGINT
+	exitCodeCancelled = 130
+)
+
+const (
+	defaultConfigFile = ".monorepo-toolkit.yml"
+)
+
+// readConfigFile merges the file given by the "config" key into v,
+// flags and environment variables still take precedence over it
+func readConfigFile(v *viper.Viper) error {
+	path := v.GetString("config")
+	if path == "" {
+		return nil
+	}
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) && path == defaultConfigFile {
+		// config file is optional
+		return nil
+	}
+	v.SetConfigFile(path)
+	err = v.ReadInConfig()
+	if err != nil {
+		return errors.Wrapf(err, `can't read config file "%s"`, path)
+	}
+	return nil
+}
+
 func er(msg interface{}) {
+	erWithCode(exitCodeError, msg)
+}
+
+func erWithCode(code int, msg interface{}) {
 	fmt.Fprintln(os.Stderr, "Error:", msg)
-	os.Exit(1)
+	os.Exit(code)
+}
+
+// exitCodeOf maps an error returned from a CI controller to an exit code
+func exitCodeOf(err error) int {
+	switch errors.Cause(err) {
+	case interactor.ErrBuildFailed:
+		return exitCodeBuildFailed
+	case interactor.ErrBuildTimeout:
+		return exitCodeBuildTimeout
+	case interactor.ErrNoChanges:
+		return exitCodeNoChanges
+	case interactor.ErrBuildCancelled, context.Canceled:
+		return exitCodeCancelled
+	}
+	switch cause := errors.Cause(err).(type) {
+	case *interactor.APIError:
+		if errors.Cause(cause.Err) == context.Canceled {
+			// a request is interrupted, e.g., by SIGINT
+			return exitCodeCancelled
+		}
+		return exitCodeAPIError
+	case *interactor.ConfigError:
+		return exitCodeConfigError
+	default:
+		return exitCodeError
+	}
+}
+
+// contextWithSignals returns a context cancelled on SIGINT or SIGTERM,
+// a second signal exits immediately without waiting for the cleanup
+func contextWithSignals(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-sigs:
+			fmt.Fprintf(os.Stderr, "Received %s, cancelling...\n", sig)
+			cancel()
+		case <-stop:
+			return
+		}
+		select {
+		case sig := <-sigs:
+			erWithCode(exitCodeCancelled, fmt.Sprintf("received %s again, exit without cleanup", sig))
+		case <-stop:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(stop)
+			cancel()
+		})
+	}
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestExitCodeOf(t *testing.T) {
+	cases := []*struct {
+		err  error
+		code int
+	}{
+		{err: interactor.ErrBuildFailed, code: exitCodeBuildFailed},
+		{err: errors.Wrap(interactor.ErrBuildTimeout, "can't build"), code: exitCodeBuildTimeout},
+		{err: interactor.ErrNoChanges, code: exitCodeNoChanges},
+		{err: interactor.ErrBuildCancelled, code: exitCodeCancelled},
+		{err: errors.Wrap(context.Canceled, "can't list changes"), code: exitCodeCancelled},
+		{err: errors.Wrap(&interactor.APIError{Err: errors.New("502 Bad Gateway")}, "can't dispatch event"), code: exitCodeAPIError},
+		{err: errors.Wrap(&interactor.APIError{Err: context.Canceled}, "can't get last succesful commit"), code: exitCodeCancelled},
+		{err: &interactor.APIError{Err: errors.Wrap(context.Canceled, "can't list workflow runs")}, code: exitCodeCancelled},
+		{err: &interactor.ConfigError{Err: errors.New("CI tool does not support commenting")}, code: exitCodeConfigError},
+		{err: errors.New("can't list changes"), code: exitCodeError},
+	}
+
+	for i, v := range cases {
+		var (
+			err  = v.err
+			want = v.code
+		)
+		t.Run(fmt.Sprintf("Case %d, calls exitCodeOf", i+1), func(t *testing.T) {
+			got := exitCodeOf(err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestContextWithSignals(t *testing.T) {
+	ctx, cancel := contextWithSignals(context.Background())
+	defer cancel()
+
+	err := syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	assert.NoError(t, err)
+
+	select {
+	case <-ctx.Done():
+		assert.Equal(t, context.Canceled, ctx.Err())
+	case <-time.After(time.Second):
+		t.Fatal("context should be cancelled on SIGTERM")
+	}
+}
@@ -3,6 +3,7 @@ module github.com/whatthefar/monorepo-toolkit
 go 1.14
 
 require (
+	github.com/go-git/go-billy/v5 v5.0.0
 	github.com/go-git/go-git/v5 v5.1.0
 	github.com/golang/mock v1.4.3
 	github.com/google/go-github/v32 v32.0.0
@@ -11,5 +12,7 @@ require (
 	github.com/spf13/cobra v1.0.0
 	github.com/spf13/viper v1.7.0
 	github.com/stretchr/testify v1.6.1
+	golang.org/x/crypto v0.0.0-20200302210943-78000ba7a073
 	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45
+	gopkg.in/yaml.v2 v2.2.4
 )
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"time"
+)
+
+// BuildState is a state of a build reported by a CI provider
+type BuildState string
+
+const (
+	BuildStateQueued    BuildState = "queued"
+	BuildStateRunning   BuildState = "running"
+	BuildStateSuccess   BuildState = "success"
+	BuildStateFailed    BuildState = "failed"
+	BuildStateCancelled BuildState = "cancelled"
+	BuildStateSkipped   BuildState = "skipped"
+	BuildStateTimedOut  BuildState = "timed-out"
+	// the CI provider reports a state this toolkit does not know
+	BuildStateUnknown BuildState = "unknown"
+)
+
+// IsFinished checks if a build in the state will not change anymore
+func (s BuildState) IsFinished() bool {
+	switch s {
+	case BuildStateSuccess, BuildStateFailed, BuildStateCancelled, BuildStateSkipped, BuildStateTimedOut:
+		return true
+	default:
+		return false
+	}
+}
+
+type BuildStatus struct {
+	State BuildState
+	// zero if the build has not started yet
+	StartedAt time.Time
+	// zero if the build has not finished yet
+	FinishedAt time.Time
+	// link to the build page of the CI provider, empty if not available
+	WebURL string
+}
+
+// Duration returns how long a finished build took, zero if it is unknown
+func (s *BuildStatus) Duration() time.Duration {
+	if s.StartedAt.IsZero() || s.FinishedAt.IsZero() {
+		return 0
+	}
+	return s.FinishedAt.Sub(s.StartedAt)
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildState_IsFinished(t *testing.T) {
+	cases := []*struct {
+		state    BuildState
+		finished bool
+	}{
+		{state: BuildStateQueued, finished: false},
+		{state: BuildStateRunning, finished: false},
+		{state: BuildStateSuccess, finished: true},
+		{state: BuildStateFailed, finished: true},
+		{state: BuildStateCancelled, finished: true},
+		{state: BuildStateSkipped, finished: true},
+		{state: BuildStateTimedOut, finished: true},
+		{state: BuildStateUnknown, finished: false},
+	}
+
+	for i, v := range cases {
+		var (
+			state = v.state
+			want  = v.finished
+		)
+		t.Run(fmt.Sprintf("Case %d, calls IsFinished on %s", i+1, state), func(t *testing.T) {
+			assert.Equal(t, want, state.IsFinished())
+		})
+	}
+}
+
+func TestBuildStatus_Duration(t *testing.T) {
+	startedAt := time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	status := &BuildStatus{State: BuildStateRunning, StartedAt: startedAt}
+	assert.Equal(t, time.Duration(0), status.Duration())
+
+	status = &BuildStatus{State: BuildStateSuccess, StartedAt: startedAt, FinishedAt: startedAt.Add(90 * time.Second)}
+	assert.Equal(t, 90*time.Second, status.Duration())
+}
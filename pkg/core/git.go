@@ -14,4 +14,7 @@ type GitGateway interface {
 	DiffNameOnly(from, to Hash) ([]string, error)
 	EnsureHavingCommitFromTip(ctx context.Context, sha Hash) error
 	IsNoCommit(err error) bool
+	// checks if ancestor is reachable from commit, or is the same commit
+	// outputs false if ancestor is not found, e.g., it has been force-pushed away
+	IsAncestor(ctx context.Context, ancestor, commit Hash) (bool, error)
 }
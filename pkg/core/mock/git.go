@@ -78,6 +78,21 @@ func (mr *MockGitGatewayMockRecorder) FilesNameOnly(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilesNameOnly", reflect.TypeOf((*MockGitGateway)(nil).FilesNameOnly), arg0)
 }
 
+// IsAncestor mocks base method
+func (m *MockGitGateway) IsAncestor(arg0 context.Context, arg1, arg2 core.Hash) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAncestor", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAncestor indicates an expected call of IsAncestor
+func (mr *MockGitGatewayMockRecorder) IsAncestor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAncestor", reflect.TypeOf((*MockGitGateway)(nil).IsAncestor), arg0, arg1, arg2)
+}
+
 // IsNoCommit mocks base method
 func (m *MockGitGateway) IsNoCommit(arg0 error) bool {
 	m.ctrl.T.Helper()
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/whatthefar/monorepo-toolkit/pkg/core (interfaces: PipelineGateway)
+// Source: github.com/whatthefar/monorepo-toolkit/pkg/core (interfaces: PipelineGateway,BuildLogGateway,CommitStatusGateway,PullRequestGateway)
 
 // Package mock_core is a generated GoMock package.
 package mock_core
@@ -9,6 +9,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	core "github.com/whatthefar/monorepo-toolkit/pkg/core"
 	reflect "reflect"
+	time "time"
 )
 
 // MockPipelineGateway is a mock of PipelineGateway interface
@@ -34,11 +35,26 @@ func (m *MockPipelineGateway) EXPECT() *MockPipelineGatewayMockRecorder {
 	return m.recorder
 }
 
+// BuildRequestFor mocks base method
+func (m *MockPipelineGateway) BuildRequestFor(arg0 string) (*core.BuildRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildRequestFor", arg0)
+	ret0, _ := ret[0].(*core.BuildRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildRequestFor indicates an expected call of BuildRequestFor
+func (mr *MockPipelineGatewayMockRecorder) BuildRequestFor(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildRequestFor", reflect.TypeOf((*MockPipelineGateway)(nil).BuildRequestFor), arg0)
+}
+
 // BuildStatus mocks base method
-func (m *MockPipelineGateway) BuildStatus(arg0 context.Context, arg1 string) (*string, error) {
+func (m *MockPipelineGateway) BuildStatus(arg0 context.Context, arg1 string) (*core.BuildStatus, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "BuildStatus", arg0, arg1)
-	ret0, _ := ret[0].(*string)
+	ret0, _ := ret[0].(*core.BuildStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -49,6 +65,20 @@ func (mr *MockPipelineGatewayMockRecorder) BuildStatus(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildStatus", reflect.TypeOf((*MockPipelineGateway)(nil).BuildStatus), arg0, arg1)
 }
 
+// BuildURL mocks base method
+func (m *MockPipelineGateway) BuildURL(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildURL", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BuildURL indicates an expected call of BuildURL
+func (mr *MockPipelineGatewayMockRecorder) BuildURL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildURL", reflect.TypeOf((*MockPipelineGateway)(nil).BuildURL), arg0)
+}
+
 // CurrentCommit mocks base method
 func (m *MockPipelineGateway) CurrentCommit() core.Hash {
 	m.ctrl.T.Helper()
@@ -63,6 +93,21 @@ func (mr *MockPipelineGatewayMockRecorder) CurrentCommit() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentCommit", reflect.TypeOf((*MockPipelineGateway)(nil).CurrentCommit))
 }
 
+// IsRetryable mocks base method
+func (m *MockPipelineGateway) IsRetryable(arg0 error) (bool, time.Duration) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRetryable", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(time.Duration)
+	return ret0, ret1
+}
+
+// IsRetryable indicates an expected call of IsRetryable
+func (mr *MockPipelineGatewayMockRecorder) IsRetryable(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRetryable", reflect.TypeOf((*MockPipelineGateway)(nil).IsRetryable), arg0)
+}
+
 // KillBuild mocks base method
 func (m *MockPipelineGateway) KillBuild(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -106,3 +151,145 @@ func (mr *MockPipelineGatewayMockRecorder) TriggerBuild(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerBuild", reflect.TypeOf((*MockPipelineGateway)(nil).TriggerBuild), arg0, arg1)
 }
+
+// MockBuildLogGateway is a mock of BuildLogGateway interface
+type MockBuildLogGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockBuildLogGatewayMockRecorder
+}
+
+// MockBuildLogGatewayMockRecorder is the mock recorder for MockBuildLogGateway
+type MockBuildLogGatewayMockRecorder struct {
+	mock *MockBuildLogGateway
+}
+
+// NewMockBuildLogGateway creates a new mock instance
+func NewMockBuildLogGateway(ctrl *gomock.Controller) *MockBuildLogGateway {
+	mock := &MockBuildLogGateway{ctrl: ctrl}
+	mock.recorder = &MockBuildLogGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBuildLogGateway) EXPECT() *MockBuildLogGatewayMockRecorder {
+	return m.recorder
+}
+
+// FailedLogTail mocks base method
+func (m *MockBuildLogGateway) FailedLogTail(arg0 context.Context, arg1 string, arg2 int) ([]*core.LogExcerpt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailedLogTail", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.LogExcerpt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailedLogTail indicates an expected call of FailedLogTail
+func (mr *MockBuildLogGatewayMockRecorder) FailedLogTail(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailedLogTail", reflect.TypeOf((*MockBuildLogGateway)(nil).FailedLogTail), arg0, arg1, arg2)
+}
+
+// MockCommitStatusGateway is a mock of CommitStatusGateway interface
+type MockCommitStatusGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommitStatusGatewayMockRecorder
+}
+
+// MockCommitStatusGatewayMockRecorder is the mock recorder for MockCommitStatusGateway
+type MockCommitStatusGatewayMockRecorder struct {
+	mock *MockCommitStatusGateway
+}
+
+// NewMockCommitStatusGateway creates a new mock instance
+func NewMockCommitStatusGateway(ctrl *gomock.Controller) *MockCommitStatusGateway {
+	mock := &MockCommitStatusGateway{ctrl: ctrl}
+	mock.recorder = &MockCommitStatusGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCommitStatusGateway) EXPECT() *MockCommitStatusGatewayMockRecorder {
+	return m.recorder
+}
+
+// SetProjectStatus mocks base method
+func (m *MockCommitStatusGateway) SetProjectStatus(arg0 context.Context, arg1 string, arg2 *core.ProjectStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProjectStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProjectStatus indicates an expected call of SetProjectStatus
+func (mr *MockCommitStatusGatewayMockRecorder) SetProjectStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProjectStatus", reflect.TypeOf((*MockCommitStatusGateway)(nil).SetProjectStatus), arg0, arg1, arg2)
+}
+
+// MockPullRequestGateway is a mock of PullRequestGateway interface
+type MockPullRequestGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockPullRequestGatewayMockRecorder
+}
+
+// MockPullRequestGatewayMockRecorder is the mock recorder for MockPullRequestGateway
+type MockPullRequestGatewayMockRecorder struct {
+	mock *MockPullRequestGateway
+}
+
+// NewMockPullRequestGateway creates a new mock instance
+func NewMockPullRequestGateway(ctrl *gomock.Controller) *MockPullRequestGateway {
+	mock := &MockPullRequestGateway{ctrl: ctrl}
+	mock.recorder = &MockPullRequestGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPullRequestGateway) EXPECT() *MockPullRequestGatewayMockRecorder {
+	return m.recorder
+}
+
+// CommentAffectedProjects mocks base method
+func (m *MockPullRequestGateway) CommentAffectedProjects(arg0 context.Context, arg1 int, arg2 *core.AffectedProjectsReport) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommentAffectedProjects", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommentAffectedProjects indicates an expected call of CommentAffectedProjects
+func (mr *MockPullRequestGatewayMockRecorder) CommentAffectedProjects(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommentAffectedProjects", reflect.TypeOf((*MockPullRequestGateway)(nil).CommentAffectedProjects), arg0, arg1, arg2)
+}
+
+// LatestBuildURLs mocks base method
+func (m *MockPullRequestGateway) LatestBuildURLs(arg0 context.Context, arg1 []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestBuildURLs", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestBuildURLs indicates an expected call of LatestBuildURLs
+func (mr *MockPullRequestGatewayMockRecorder) LatestBuildURLs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestBuildURLs", reflect.TypeOf((*MockPullRequestGateway)(nil).LatestBuildURLs), arg0, arg1)
+}
+
+// PullRequestNumber mocks base method
+func (m *MockPullRequestGateway) PullRequestNumber() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullRequestNumber")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// PullRequestNumber indicates an expected call of PullRequestNumber
+func (mr *MockPullRequestGatewayMockRecorder) PullRequestNumber() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestNumber", reflect.TypeOf((*MockPullRequestGateway)(nil).PullRequestNumber))
+}
@@ -1,9 +1,10 @@
-//go:generate mockgen -destination mock/pipeline.go . PipelineGateway
+//go:generate mockgen -destination mock/pipeline.go . PipelineGateway,BuildLogGateway,CommitStatusGateway,PullRequestGateway
 
 package core
 
 import (
 	"context"
+	"time"
 )
 
 type PipelineGateway interface {
@@ -12,12 +13,109 @@ type PipelineGateway interface {
 	// get hash of current commit
 	CurrentCommit() Hash
 
+	// describe the request TriggerBuild would send for given project, without sending it
+	BuildRequestFor(projectName string) (*BuildRequest, error)
 	// start build of given project
 	// outputs build request id
 	TriggerBuild(ctx context.Context, projectName string) (*string, error)
+	// get link to the page of build identified by given build number
+	// outputs empty string if not available
+	BuildURL(buildID string) string
 	// get status of build identified by given build number
-	// outputs one of: success | failed | null
-	BuildStatus(ctx context.Context, buildID string) (*string, error)
+	BuildStatus(ctx context.Context, buildID string) (*BuildStatus, error)
 	// kills running build identified by given build number
 	KillBuild(ctx context.Context, buildID string) error
+
+	// checks if a request failed with the given error can be retried, e.g., rate limits,
+	// server errors or network timeouts
+	// outputs how long the server asks to wait before retrying, zero if not specified
+	IsRetryable(err error) (retryable bool, wait time.Duration)
+}
+
+// BuildLogGateway is an optional capability of a PipelineGateway to read logs of builds
+type BuildLogGateway interface {
+	// get the last lines of logs of failed steps of a finished build
+	FailedLogTail(ctx context.Context, buildID string, lines int) ([]*LogExcerpt, error)
+}
+
+// CommitStatusGateway is an optional capability of a PipelineGateway to report the status of
+// each project on the current commit, e.g., to be required by branch protection
+type CommitStatusGateway interface {
+	// create or update the status of given project on the current commit
+	SetProjectStatus(ctx context.Context, projectName string, status *ProjectStatus) error
+}
+
+type CommitState string
+
+const (
+	CommitStatePending CommitState = "pending"
+	CommitStateSuccess CommitState = "success"
+	CommitStateFailure CommitState = "failure"
+	// the project is not affected by changes
+	CommitStateNeutral CommitState = "neutral"
+)
+
+type ProjectStatus struct {
+	State       CommitState
+	Description string
+	// link to the build, empty if not available
+	TargetURL string
+}
+
+// PullRequestGateway is an optional capability of a PipelineGateway to comment on the pull request
+// being built
+type PullRequestGateway interface {
+	// get the number of the pull request being built, zero if not built for a pull request
+	PullRequestNumber() int
+	// get links to the latest builds of given projects, by project name, missing if none is found
+	LatestBuildURLs(ctx context.Context, projectNames []string) (map[string]string, error)
+	// post a report of affected projects on given pull request, or update the one posted before
+	// outputs link to the comment
+	CommentAffectedProjects(ctx context.Context, number int, report *AffectedProjectsReport) (string, error)
+}
+
+// AffectedProjectsReport describes projects affected by changes of a pull request
+type AffectedProjectsReport struct {
+	// empty if no successful build is found, all files of HeadCommit are considered changed
+	BaseCommit Hash
+	HeadCommit Hash
+	Projects   []*AffectedProject
+}
+
+// AffectReason tells why a project is affected by changes
+type AffectReason string
+
+const (
+	// files under the path of the project are changed
+	AffectedByChange AffectReason = "change"
+	// a file the project depends on is changed, i.e., a file matching a global trigger
+	AffectedByDependency AffectReason = "dependency"
+)
+
+type AffectedProject struct {
+	Name   string
+	Path   string
+	Reason AffectReason
+	// changed files under the path, or the changed file matching a global trigger
+	Files []string
+	// the global trigger matched by a changed file, if affected by a dependency
+	Trigger string
+	// link to the latest build of the project, empty if not found
+	BuildURL string
+}
+
+// LogExcerpt is a part of a log of a build step
+type LogExcerpt struct {
+	Job   string
+	Step  string
+	Lines []string
+}
+
+// BuildRequest describes what is sent to a CI provider to start a build
+type BuildRequest struct {
+	ProjectName string
+	// event type or job name known by the CI provider
+	Event string
+	// payload in JSON
+	Payload string
 }
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy defines how many times, and how long to wait between attempts,
+// a request failed with a retryable error is retried
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+var (
+	DefaultRetryPolicy = RetryPolicy{
+		MaxRetries: 5,
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   1 * time.Minute,
+	}
+)
+
+// Backoff returns a delay before the given retry attempt, starting from 1.
+// The delay grows exponentially up to MaxDelay, with a random jitter of up to half of it.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxRetries: 5,
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   10 * time.Second,
+	}
+	cases := []*struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 500 * time.Millisecond, max: 1 * time.Second},
+		{attempt: 2, min: 1 * time.Second, max: 2 * time.Second},
+		{attempt: 3, min: 2 * time.Second, max: 4 * time.Second},
+		// capped by MaxDelay
+		{attempt: 5, min: 5 * time.Second, max: 10 * time.Second},
+		{attempt: 100, min: 5 * time.Second, max: 10 * time.Second},
+	}
+
+	for i, v := range cases {
+		var (
+			attempt = v.attempt
+			min     = v.min
+			max     = v.max
+		)
+		t.Run(fmt.Sprintf("Case %d, Backoff should be in range", i+1), func(t *testing.T) {
+			for n := 0; n < 100; n++ {
+				got := policy.Backoff(attempt)
+				assert.GreaterOrEqual(t, int64(got), int64(min))
+				assert.LessOrEqual(t, int64(got), int64(max))
+			}
+		})
+	}
+}
@@ -3,9 +3,16 @@
 package factory
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/whatthefar/monorepo-toolkit/pkg/git"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 	interactor_impl "github.com/whatthefar/monorepo-toolkit/pkg/interactor/impl"
 	"github.com/whatthefar/monorepo-toolkit/pkg/interface/controller"
 	"github.com/whatthefar/monorepo-toolkit/pkg/interface/presenter"
@@ -16,30 +23,295 @@ var (
 )
 
 type CIControllerFactory interface {
-	New(gitWorkDir string, tool string) (controller.CI, error)
+	New(gitWorkDir string, tool string, config Config) (controller.CI, error)
+}
+
+type Config struct {
+	// glob patterns of files that mark every project as changed when touched
+	GlobalTriggers []string
+	// maximum number of builds running at the same time, unlimited if zero
+	MaxParallel int
+	// number of times a failed build is re-triggered before it is considered failed
+	Retries int
+	// wait for all builds even if some have failed, and report a summary
+	KeepGoing bool
+	// fail with interactor.ErrNoChanges when no project has changes
+	ErrorOnNoChanges bool
+	// report build requests instead of triggering builds
+	DryRun bool
+	// number of log lines of failed steps reported for failed builds, disabled if zero
+	FailureLogLines int
+	// maximum time to wait for a build, the interactor default if zero
+	Timeout time.Duration
+	// interval between build status checks, the interactor default if zero
+	PollInterval time.Duration
+	// timeout and polling overrides, by project name
+	Projects map[string]ProjectConfig
+	// output format of presenters, text if empty
+	Output presenter.Format
+	// additional outputs of builds, along with the one to stdout
+	Outputs []OutputConfig
+	// how the status of each project is reported on the current commit, disabled if empty
+	CommitStatus string
+	// how builds are dispatched, the default of the CI tool if empty
+	Dispatch string
+	// file name of the workflow dispatched for each project, where "{project}" is replaced by a project name
+	DispatchWorkflow string
+	// additional inputs of dispatched workflows
+	DispatchInputs map[string]interface{}
+	// maximum time to wait for the run created by a dispatch event, the default of the CI tool if zero
+	DispatchWait time.Duration
+	// maximum number of successful builds looked back for the last successful commit,
+	// the default of the CI tool if zero
+	LookbackRuns int
+	// event of builds considered for the last successful commit, any event if empty
+	SuccessEvent string
+}
+
+// OutputConfig enables an output of builds written to a file, or posted to a webhook
+type OutputConfig struct {
+	// one of Output* kinds
+	Kind string
+	// required, except for OutputStepSummary, which defaults to $GITHUB_STEP_SUMMARY,
+	// and OutputWebhook, for which it is a file of templates overriding default ones
+	File string
+	// incoming webhook URL for OutputWebhook, environment variables like "$SLACK_WEBHOOK_URL" are expanded
+	URL string
+	// format of messages for OutputWebhook, one of "slack", "teams" and "google-chat"
+	Format string
+	// post an update per finished project for OutputWebhook
+	Threaded bool
+}
+
+// Output kinds
+const (
+	OutputText        = string(presenter.FormatText)
+	OutputJSON        = string(presenter.FormatJSON)
+	OutputYAML        = string(presenter.FormatYAML)
+	OutputJUnit       = "junit"
+	OutputStepSummary = "step-summary"
+	OutputWebhook     = "webhook"
+)
+
+type ProjectConfig struct {
+	Timeout      time.Duration
+	PollInterval time.Duration
 }
 
 type ciControllerFactory struct{}
 
-func (f *ciControllerFactory) New(gitWorkDir string, tool string) (controller.CI, error) {
+func (f *ciControllerFactory) New(gitWorkDir string, tool string, config Config) (controller.CI, error) {
 	git, err := git.NewGitGateway(gitWorkDir)
 	if err != nil {
 		return nil, err
 	}
-	pipeline, err := NewPipeline(tool)
+	pipeline, err := NewPipeline(tool, git, PipelineConfig{
+		CommitStatus:     config.CommitStatus,
+		Dispatch:         config.Dispatch,
+		DispatchWorkflow: config.DispatchWorkflow,
+		DispatchInputs:   config.DispatchInputs,
+		DispatchWait:     config.DispatchWait,
+		LookbackRuns:     config.LookbackRuns,
+		SuccessEvent:     config.SuccessEvent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	listChangesConfig := interactor_impl.ListChangesConfig{
+		GlobalTriggers: config.GlobalTriggers,
+	}
+	listChangesPresenter, err := newListChangesPresenter(config.Output)
 	if err != nil {
 		return nil, err
 	}
-	presenter := presenter.NewBuildProjectsPresenter(os.Stdout)
 	listChangesIt := interactor_impl.NewListChangesInteractor(
 		git,
 		pipeline,
+		listChangesPresenter,
+		listChangesConfig,
 	)
+	buildProjectsPresenter, err := newBuildProjectsPresenter(tool, config)
+	if err != nil {
+		return nil, err
+	}
 	buildProjectsIt := interactor_impl.NewBuildProjectsInteractor(
 		git,
 		pipeline,
-		presenter,
+		buildProjectsPresenter,
+		interactor_impl.BuildProjectsConfig{
+			ListChangesConfig:  listChangesConfig,
+			MaxParallel:        config.MaxParallel,
+			Retries:            config.Retries,
+			KeepGoing:          config.KeepGoing,
+			ErrorOnNoChanges:   config.ErrorOnNoChanges,
+			DryRun:             config.DryRun,
+			FailureLogLines:    config.FailureLogLines,
+			Timeout:            config.Timeout,
+			PollInterval:       config.PollInterval,
+			Projects:           projectConfigsOf(config.Projects),
+			ReportCommitStatus: config.CommitStatus != "",
+		},
 	)
 	ctrl := controller.NewCIController(listChangesIt, buildProjectsIt)
 	return ctrl, nil
 }
+
+func newListChangesPresenter(format presenter.Format) (interactor.ListChangesOutput, error) {
+	switch format {
+	case presenter.FormatJSON, presenter.FormatYAML:
+		return presenter.NewEncodedListChangesPresenter(os.Stdout, format), nil
+	case presenter.FormatGitHubMatrix:
+		var githubOutput io.Writer
+		if path := os.Getenv("GITHUB_OUTPUT"); path != "" {
+			// the file is shared by all steps of a job, and closed on exit
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, errors.Wrapf(err, `can't open "GITHUB_OUTPUT" file %s`, path)
+			}
+			githubOutput = f
+		}
+		return presenter.NewGitHubMatrixListChangesPresenter(os.Stdout, githubOutput, os.Stderr), nil
+	default:
+		// project names are printed to stdout, keep it clean for piping
+		return presenter.NewListChangesPresenter(os.Stdout, os.Stderr), nil
+	}
+}
+
+func newBuildProjectsPresenter(tool string, config Config) (interactor.BuildProjectsOutput, error) {
+	onGitHubActions := tool == "github" && os.Getenv("GITHUB_ACTIONS") == "true"
+	var stdout *presenter.Sink
+	switch {
+	case config.Output == presenter.FormatJSON || config.Output == presenter.FormatYAML:
+		stdout = presenter.NewSink(string(config.Output), os.Stdout, encodedBuildProjectsPresenterOf(config.Output))
+	case onGitHubActions:
+		stdout = presenter.NewSink(OutputText, os.Stdout, presenter.NewGitHubActionsBuildProjectsPresenter)
+	case presenter.IsTerminal(os.Stdout):
+		stdout = presenter.NewSink(OutputText, os.Stdout, presenter.NewTTYBuildProjectsPresenter)
+	default:
+		stdout = presenter.NewSink(OutputText, os.Stdout, presenter.NewBuildProjectsPresenter)
+	}
+	sinks := []*presenter.Sink{stdout}
+
+	outputs := config.Outputs
+	if onGitHubActions && os.Getenv("GITHUB_STEP_SUMMARY") != "" && !hasOutput(outputs, OutputStepSummary) {
+		outputs = append(outputs, OutputConfig{Kind: OutputStepSummary})
+	}
+	for _, output := range outputs {
+		// nothing is built in dry-run mode, so there is nothing to report
+		if output.Kind == OutputJUnit && config.DryRun == true {
+			continue
+		}
+		sink, err := newSink(output)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return presenter.NewMultiBuildProjectsPresenter(os.Stderr, sinks...), nil
+}
+
+func newSink(output OutputConfig) (*presenter.Sink, error) {
+	if output.Kind == OutputWebhook {
+		return newWebhookSink(output)
+	}
+	var newOutput func(w io.Writer) interactor.BuildProjectsOutput
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch output.Kind {
+	case OutputText:
+		newOutput = presenter.NewBuildProjectsPresenter
+	case OutputJSON, OutputYAML:
+		newOutput = encodedBuildProjectsPresenterOf(presenter.Format(output.Kind))
+	case OutputJUnit:
+		newOutput = presenter.NewJUnitBuildProjectsPresenter
+	case OutputStepSummary:
+		if output.File == "" {
+			output.File = os.Getenv("GITHUB_STEP_SUMMARY")
+		}
+		// the job summary is shared by all steps of a job
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		repositoryURL := gitHubRepositoryURL()
+		newOutput = func(w io.Writer) interactor.BuildProjectsOutput {
+			return presenter.NewStepSummaryBuildProjectsPresenter(w, repositoryURL)
+		}
+	default:
+		return nil, errors.Errorf(`unknown output kind "%s"`, output.Kind)
+	}
+	if output.File == "" {
+		return nil, errors.Errorf(`file of output "%s" is not set`, output.Kind)
+	}
+	// the file is closed on exit
+	f, err := os.OpenFile(output.File, flag, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, `can't open file of output "%s" %s`, output.Kind, output.File)
+	}
+	return presenter.NewSink(output.Kind, f, newOutput), nil
+}
+
+// newWebhookSink creates a sink posting to a webhook, errors of posting are printed to stderr
+func newWebhookSink(output OutputConfig) (*presenter.Sink, error) {
+	webhookConfig := presenter.WebhookConfig{
+		URL:      os.ExpandEnv(output.URL),
+		Format:   output.Format,
+		Threaded: output.Threaded,
+	}
+	if webhookConfig.URL == "" {
+		return nil, errors.Errorf(`URL of output "%s" is not set`, output.Kind)
+	}
+	if output.File != "" {
+		b, err := ioutil.ReadFile(output.File)
+		if err != nil {
+			return nil, errors.Wrapf(err, `can't read templates of output "%s" %s`, output.Kind, output.File)
+		}
+		webhookConfig.Template = string(b)
+	}
+	var err error
+	sink := presenter.NewSink(output.Kind, os.Stderr, func(w io.Writer) interactor.BuildProjectsOutput {
+		var webhook interactor.BuildProjectsOutput
+		webhook, err = presenter.NewWebhookBuildProjectsPresenter(http.DefaultClient, webhookConfig, w)
+		return webhook
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, `can't create output "%s"`, output.Kind)
+	}
+	return sink, nil
+}
+
+func encodedBuildProjectsPresenterOf(format presenter.Format) func(w io.Writer) interactor.BuildProjectsOutput {
+	return func(w io.Writer) interactor.BuildProjectsOutput {
+		return presenter.NewEncodedBuildProjectsPresenter(w, format)
+	}
+}
+
+func hasOutput(outputs []OutputConfig, kind string) bool {
+	for _, v := range outputs {
+		if v.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// gitHubRepositoryURL returns the URL of the repository of the running workflow,
+// empty if not available
+func gitHubRepositoryURL() string {
+	repository := os.Getenv("GITHUB_REPOSITORY")
+	if repository == "" {
+		return ""
+	}
+	serverURL := os.Getenv("GITHUB_SERVER_URL")
+	if serverURL == "" {
+		serverURL = "https://github.com"
+	}
+	return fmt.Sprintf("%s/%s", serverURL, repository)
+}
+
+func projectConfigsOf(projects map[string]ProjectConfig) map[string]interactor_impl.ProjectConfig {
+	configs := make(map[string]interactor_impl.ProjectConfig, len(projects))
+	for name, p := range projects {
+		configs[name] = interactor_impl.ProjectConfig{
+			Timeout:      p.Timeout,
+			PollInterval: p.PollInterval,
+		}
+	}
+	return configs
+}
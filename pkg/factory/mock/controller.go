@@ -6,6 +6,7 @@ package mock_factory
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	factory "github.com/whatthefar/monorepo-toolkit/pkg/factory"
 	controller "github.com/whatthefar/monorepo-toolkit/pkg/interface/controller"
 	reflect "reflect"
 )
@@ -34,16 +35,16 @@ func (m *MockCIControllerFactory) EXPECT() *MockCIControllerFactoryMockRecorder
 }
 
 // New mocks base method
-func (m *MockCIControllerFactory) New(arg0, arg1 string) (controller.CI, error) {
+func (m *MockCIControllerFactory) New(arg0, arg1 string, arg2 factory.Config) (controller.CI, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "New", arg0, arg1)
+	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2)
 	ret0, _ := ret[0].(controller.CI)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // New indicates an expected call of New
-func (mr *MockCIControllerFactoryMockRecorder) New(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockCIControllerFactoryMockRecorder) New(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockCIControllerFactory)(nil).New), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockCIControllerFactory)(nil).New), arg0, arg1, arg2)
 }
@@ -2,13 +2,33 @@ package factory
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/whatthefar/monorepo-toolkit/pkg/core"
 	"github.com/whatthefar/monorepo-toolkit/pkg/pipeline"
 )
 
-func NewPipeline(tool string) (core.PipelineGateway, error) {
+type PipelineConfig struct {
+	// how statuses of projects are reported on the current commit, e.g., "status" or "check-run"
+	// for github, disabled if empty
+	CommitStatus string
+	// how builds are dispatched, e.g., "repository" or "workflow" for github, the default one if empty
+	Dispatch string
+	// file name of the workflow dispatched for each project, where "{project}" is replaced by a project name
+	DispatchWorkflow string
+	// additional inputs of dispatched workflows
+	DispatchInputs map[string]interface{}
+	// maximum time to wait for the run created by a dispatch event, the default of the CI tool if zero
+	DispatchWait time.Duration
+	// maximum number of successful builds looked back for the last successful commit,
+	// the default of the CI tool if zero
+	LookbackRuns int
+	// event of builds considered for the last successful commit, any event if empty
+	SuccessEvent string
+}
+
+func NewPipeline(tool string, git core.GitGateway, config PipelineConfig) (core.PipelineGateway, error) {
 	switch tool {
 	case "bitbucket":
 		return nil, errors.New(fmt.Sprintf(`CI_TOOl "%s" is not currently supported`, tool))
@@ -20,7 +40,29 @@ func NewPipeline(tool string) (core.PipelineGateway, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "fail to validate envs for github action")
 		}
-		return pipeline.NewGitHubActionGateway(env), nil
+		switch config.CommitStatus {
+		case "", pipeline.GitHubCommitStatus, pipeline.GitHubCheckRun:
+		default:
+			return nil, errors.Errorf(`commit status "%s" is not supported by github`, config.CommitStatus)
+		}
+		switch config.Dispatch {
+		case "", pipeline.GitHubRepositoryDispatch:
+		case pipeline.GitHubWorkflowDispatch:
+			if config.DispatchWorkflow == "" {
+				return nil, errors.New(`workflow file is required to dispatch workflows of projects`)
+			}
+		default:
+			return nil, errors.Errorf(`dispatch "%s" is not supported by github`, config.Dispatch)
+		}
+		return pipeline.NewGitHubActionGateway(env, git, pipeline.GitHubActionConfig{
+			CommitStatus:     config.CommitStatus,
+			Dispatch:         config.Dispatch,
+			DispatchWorkflow: config.DispatchWorkflow,
+			DispatchInputs:   config.DispatchInputs,
+			DispatchWait:     config.DispatchWait,
+			LookbackRuns:     config.LookbackRuns,
+			SuccessEvent:     config.SuccessEvent,
+		}), nil
 	case "travis":
 		return nil, errors.New(fmt.Sprintf(`CI_TOOl "%s" is not currently supported`, tool))
 	default:
@@ -44,7 +44,25 @@ func (g *gitGateway) EnsureHavingCommitFromTip(ctx context.Context, sha core.Has
 		return nil
 	}
 
-	shallows, err := g.repo.Storer.Shallow()
+	err = g.fetchAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	hasCommit, err = g.hasCommit(sha)
+	if err != nil {
+		return errors.Wrapf(err, `can't check is there a commit "%s"`, sha)
+	}
+	if hasCommit != true {
+		return errors.Wrapf(ErrNoCommit, `commit "%s" not found`, sha)
+	}
+
+	return nil
+}
+
+// fetchAll fetches the whole history, a shallow repository is cloned again
+func (g *gitGateway) fetchAll(ctx context.Context) error {
+	shallows, _ := g.repo.Storer.Shallow()
 	if shallows != nil {
 		remoteName := "origin"
 		remote, err := g.repo.Remote(remoteName)
@@ -65,20 +83,46 @@ func (g *gitGateway) EnsureHavingCommitFromTip(ctx context.Context, sha core.Has
 		g.repo = newRepo
 	} else {
 		err := g.repo.FetchContext(ctx, &gogit.FetchOptions{})
-		if err != nil {
+		if err != nil && err != gogit.NoErrAlreadyUpToDate {
 			return errors.Wrap(err, "can't fetch")
 		}
 	}
+	return nil
+}
 
-	hasCommit, err = g.hasCommit(sha)
+func (g *gitGateway) IsAncestor(ctx context.Context, ancestor core.Hash, commit core.Hash) (bool, error) {
+	err := g.EnsureHavingCommitFromTip(ctx, ancestor)
+	if g.IsNoCommit(err) {
+		return false, nil
+	}
 	if err != nil {
-		return errors.Wrapf(err, `can't check is there a commit "%s"`, sha)
+		return false, errors.Wrapf(err, `can't get commit "%s"`, ancestor)
 	}
-	if hasCommit != true {
-		return errors.Wrapf(ErrNoCommit, `commit "%s" not found`, sha)
+	isAncestor, err := g.isAncestor(ancestor, commit)
+	if errors.Cause(err) == plumbing.ErrObjectNotFound {
+		// the history of a shallow repository might end before reaching the ancestor
+		err = g.fetchAll(ctx)
+		if err != nil {
+			return false, err
+		}
+		isAncestor, err = g.isAncestor(ancestor, commit)
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, `can't check if commit "%s" is an ancestor of "%s"`, ancestor, commit)
 	}
+	return isAncestor, nil
+}
 
-	return nil
+func (g *gitGateway) isAncestor(ancestor core.Hash, commit core.Hash) (bool, error) {
+	ancestorCommit, err := g.repo.CommitObject(plumbing.NewHash(string(ancestor)))
+	if err != nil {
+		return false, errors.Wrap(err, "can't get commit object of the `ancestor` hash")
+	}
+	commitCommit, err := g.repo.CommitObject(plumbing.NewHash(string(commit)))
+	if err != nil {
+		return false, errors.Wrap(err, "can't get commit object of the `commit` hash")
+	}
+	return ancestorCommit.IsAncestor(commitCommit)
 }
 
 func (g *gitGateway) IsNoCommit(err error) bool {
@@ -165,5 +209,11 @@ func diffNameOnly(from *object.Tree, to *object.Tree) ([]string, error) {
 			changedPaths = append(changedPaths, v.To.Name)
 		}
 	}
-	return changedPaths, nil
+
+	// paths matching .monorepoignore files of the `to` tree never trigger builds
+	patterns, err := readIgnorePatterns(to)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read .monorepoignore patterns of the `to` tree")
+	}
+	return filterIgnoredPaths(patterns, changedPaths), nil
 }
@@ -3,8 +3,15 @@ package git
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 
@@ -106,6 +113,63 @@ func TestGitGateway_hasCommit(t *testing.T) {
 	})
 }
 
+func TestGitGateway_IsAncestor(t *testing.T) {
+	Convey("Given a repository whose branch is reset and committed again", t, func() {
+		ctx := context.Background()
+		dir, err := ioutil.TempDir("", "monorepo-toolkit")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		repo, err := gogit.PlainInit(dir, false)
+		So(err, ShouldBeNil)
+		worktree, err := repo.Worktree()
+		So(err, ShouldBeNil)
+		commit := func(content string) plumbing.Hash {
+			err := ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644)
+			So(err, ShouldBeNil)
+			_, err = worktree.Add("README.md")
+			So(err, ShouldBeNil)
+			hash, err := worktree.Commit(content, &gogit.CommitOptions{
+				Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+			})
+			So(err, ShouldBeNil)
+			return hash
+		}
+		base := commit("base")
+		forced := commit("force-pushed away")
+		So(worktree.Reset(&gogit.ResetOptions{Commit: base, Mode: gogit.HardReset}), ShouldBeNil)
+		head := commit("head")
+
+		git, err := NewGitGateway(dir)
+		So(err, ShouldBeNil)
+
+		cases := []*struct {
+			ancestor plumbing.Hash
+			want     bool
+		}{
+			{ancestor: base, want: true},
+			{ancestor: head, want: true},
+			{ancestor: forced, want: false},
+		}
+
+		for i, v := range cases {
+			var (
+				ancestor = v.ancestor
+				want     = v.want
+			)
+
+			Convey(fmt.Sprintf("Case %d, when calls IsAncestor of the head commit", i+1), func() {
+				got, err := git.IsAncestor(ctx, core.Hash(ancestor.String()), core.Hash(head.String()))
+
+				Convey("It should check if the commit is reachable from the head commit", func() {
+					So(err, ShouldBeNil)
+					So(got, ShouldEqual, want)
+				})
+			})
+		}
+	})
+}
+
 func TestGitGateway_FilesNameOnly(t *testing.T) {
 	Convey("Given a basic repository", t, func() {
 		type c struct {
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+const (
+	// monorepoIgnoreFile lists paths, in gitignore syntax, whose changes never trigger builds.
+	// Like .gitignore, the file can be placed in any directory and is relative to it.
+	monorepoIgnoreFile = ".monorepoignore"
+
+	ignoreCommentPrefix = "#"
+	ignorePathSeparator = "/"
+)
+
+// readIgnorePatterns reads patterns of every .monorepoignore file in the tree.
+// The result is in the ascending order of priority (last higher).
+func readIgnorePatterns(tree *object.Tree) ([]gitignore.Pattern, error) {
+	ignoreFiles := make([]string, 0)
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "can't walk a tree")
+		}
+		if entry.Mode.IsFile() && entry.Name == monorepoIgnoreFile {
+			ignoreFiles = append(ignoreFiles, name)
+		}
+	}
+
+	// nested files take precedence over their parents
+	sort.SliceStable(ignoreFiles, func(i, j int) bool {
+		return depthOf(ignoreFiles[i]) < depthOf(ignoreFiles[j])
+	})
+
+	patterns := make([]gitignore.Pattern, 0)
+	for _, name := range ignoreFiles {
+		file, err := tree.File(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, `can't get file "%s" from a tree`, name)
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return nil, errors.Wrapf(err, `can't read content of file "%s"`, name)
+		}
+		patterns = append(patterns, parseIgnorePatterns(content, domainOf(name))...)
+	}
+	return patterns, nil
+}
+
+func parseIgnorePatterns(content string, domain []string) []gitignore.Pattern {
+	patterns := make([]gitignore.Pattern, 0)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(line, ignoreCommentPrefix) || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+	return patterns
+}
+
+func filterIgnoredPaths(patterns []gitignore.Pattern, paths []string) []string {
+	if len(patterns) == 0 {
+		return paths
+	}
+	matcher := gitignore.NewMatcher(patterns)
+	filtered := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if matcher.Match(strings.Split(p, ignorePathSeparator), false) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
+// domainOf returns the directory of an ignore file, as path components.
+func domainOf(name string) []string {
+	dir := path.Dir(name)
+	if dir == "." {
+		return []string{}
+	}
+	return strings.Split(dir, ignorePathSeparator)
+}
+
+func depthOf(name string) int {
+	return strings.Count(name, ignorePathSeparator)
+}
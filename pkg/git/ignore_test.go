@@ -0,0 +1,117 @@
+package git
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterIgnoredPaths(t *testing.T) {
+	cases := []*struct {
+		ignores  map[string]string
+		paths    []string
+		filtered []string
+	}{
+		{
+			// no ignore file
+			ignores:  map[string]string{},
+			paths:    []string{"README.md", "services/app1/main.go"},
+			filtered: []string{"README.md", "services/app1/main.go"},
+		},
+		{
+			ignores: map[string]string{
+				"": "# docs\n*.md\n.editorconfig\n",
+			},
+			paths:    []string{"README.md", ".editorconfig", "services/app1/README.md", "services/app1/main.go"},
+			filtered: []string{"services/app1/main.go"},
+		},
+		{
+			// nested ignore file is relative to its directory and takes precedence
+			ignores: map[string]string{
+				"":              "CHANGELOG.md\n",
+				"services/app1": "!CHANGELOG.md\ndocs/\n",
+			},
+			paths: []string{
+				"CHANGELOG.md",
+				"services/app1/CHANGELOG.md",
+				"services/app1/docs/index.md",
+				"services/app2/CHANGELOG.md",
+				"services/app2/docs/index.md",
+			},
+			filtered: []string{
+				"services/app1/CHANGELOG.md",
+				"services/app2/docs/index.md",
+			},
+		},
+	}
+
+	for i, v := range cases {
+		var (
+			ignores = v.ignores
+			paths   = v.paths
+			want    = v.filtered
+		)
+		t.Run(fmt.Sprintf("Case %d, calls filterIgnoredPaths", i+1), func(t *testing.T) {
+			patterns := parseIgnorePatterns(ignores[""], domainOf(monorepoIgnoreFile))
+			for dir, content := range ignores {
+				if dir == "" {
+					continue
+				}
+				patterns = append(patterns, parseIgnorePatterns(content, domainOf(dir+"/"+monorepoIgnoreFile))...)
+			}
+			got := filterIgnoredPaths(patterns, paths)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestReadIgnorePatterns(t *testing.T) {
+	Convey("Given a tree with nested .monorepoignore files", t, func() {
+		fs := memfs.New()
+		repo, err := gogit.Init(memory.NewStorage(), fs)
+		So(err, ShouldBeNil)
+		files := map[string]string{
+			"services/app1/.monorepoignore": "!CHANGELOG.md\n",
+			".monorepoignore":               "CHANGELOG.md\n",
+			"services/app1/CHANGELOG.md":    "",
+			"services/app2/CHANGELOG.md":    "",
+		}
+		for name, content := range files {
+			So(util.WriteFile(fs, name, []byte(content), 0644), ShouldBeNil)
+		}
+		wt, err := repo.Worktree()
+		So(err, ShouldBeNil)
+		So(wt.AddGlob("."), ShouldBeNil)
+		hash, err := wt.Commit("init", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		So(err, ShouldBeNil)
+		commit, err := repo.CommitObject(hash)
+		So(err, ShouldBeNil)
+		tree, err := commit.Tree()
+		So(err, ShouldBeNil)
+
+		Convey("When calls readIgnorePatterns", func() {
+			patterns, err := readIgnorePatterns(tree)
+
+			Convey("It should give nested patterns a higher priority", func() {
+				So(err, ShouldBeNil)
+				So(patterns, ShouldHaveLength, 2)
+				got := filterIgnoredPaths(patterns, []string{
+					"CHANGELOG.md",
+					"services/app1/CHANGELOG.md",
+					"services/app2/CHANGELOG.md",
+				})
+				So(got, ShouldResemble, []string{"services/app1/CHANGELOG.md"})
+			})
+		})
+	})
+}
@@ -5,29 +5,106 @@ package interactor
 import (
 	"context"
 	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
 )
 
 type BuildProjectsInteractor interface {
-	BuildPaths(ctx context.Context, paths []string, workflowID string)
-	BuildPathsOnce(ctx context.Context, paths []string, workflowID string)
+	BuildPaths(ctx context.Context, paths []string, workflowID string) error
+	BuildPathsOnce(ctx context.Context, paths []string, workflowID string) error
+}
+
+var (
+	ErrBuildFailed  = errors.New("some builds failed")
+	ErrBuildTimeout = errors.New("some builds were not finished in time")
+	ErrNoChanges    = errors.New("no project has changes")
+	// ErrBuildCancelled is returned when the context is cancelled while waiting for builds
+	ErrBuildCancelled = errors.New("builds were cancelled")
+)
+
+// APIError is the cause of an error returned when a request to a CI provider fails,
+// e.g., after retries are exhausted
+type APIError struct {
+	Err error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+// ConfigError is the cause of an error returned when the configuration is not supported,
+// e.g., by the CI provider
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
 }
 
 type BuildInfo struct {
 	ProjectName string
 	BuildID     string
+	// empty if not available
+	WebURL string
+}
+
+type BuildOutcome string
+
+const (
+	BuildSucceeded BuildOutcome = "success"
+	BuildFailed    BuildOutcome = "failed"
+	BuildSkipped   BuildOutcome = "skipped"
+	BuildTimedOut  BuildOutcome = "timed-out"
+	BuildCancelled BuildOutcome = "cancelled"
+	// not triggered, since builds are stopped before
+	BuildPending BuildOutcome = "pending"
+	// triggered, but not waited for since builds are stopped before it finished
+	BuildRunning BuildOutcome = "running"
+)
+
+type BuildResult struct {
+	ProjectName string
+	// empty if no build was triggered
+	BuildID string
+	Outcome BuildOutcome
+	// zero if unknown
+	Duration time.Duration
+	// empty if not available
+	WebURL string
 }
 
+// BuildProjectsOutput is called from one goroutine at a time, it does not need to be goroutine-safe
 type BuildProjectsOutput interface {
-	BuildTriggeredFor(projectName string, buildID string)
+	ListChangesOutput
+
+	// builds are not triggered in dry-run mode, only the requests are reported
+	BuildPlanned(requests []*core.BuildRequest)
+	// webURL is empty if not available
+	BuildTriggeredFor(projectName string, buildID string, webURL string)
 	NoBuildTriggeredFor(projectName string)
+	BuildSucceededFor(projectName string, buildID string)
 	BuildFailedFor(projectName string, buildID string)
+	// excerpts of logs of failed steps, reported after BuildFailedFor
+	BuildLogFor(projectName string, buildID string, excerpts []*core.LogExcerpt)
+	BuildLogError(projectName string, err error)
+	BuildCancelledFor(projectName string, buildID string)
+	RetryingFailedBuildFor(projectName string, buildID string, attempt int, maxAttempts int)
 	BuildSkippedFor(projectName string)
 	WaitingFor(buildInfos []*BuildInfo)
 	AllBuildSucceeded(projectNames []string)
+	// reported once all builds are finished or stopped
+	BuildSummary(results []*BuildResult)
 	Timeout(waitingTime time.Duration)
+	// reported after not finished builds are killed by a timeout
+	BuildTimedOutFor(projectName string, buildID string)
 	KillingBuilds(buildInfos []*BuildInfo)
 	KillBuildError(projectName string, err error)
 	NotFinishedBuildsKilled()
-
-	ThrowError(err error)
+	// the status of a project on the current commit can't be set
+	CommitStatusError(projectName string, err error)
+	Cancelled()
+	RetryingRequest(request string, attempt int, delay time.Duration, err error)
 }
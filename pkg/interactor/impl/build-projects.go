@@ -2,6 +2,9 @@ package interactor_impl
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -13,158 +16,706 @@ func NewBuildProjectsInteractor(
 	git core.GitGateway,
 	pipeline core.PipelineGateway,
 	presenter BuildProjectsOutput,
+	config BuildProjectsConfig,
 ) BuildProjectsInteractor {
-	return &buildProjectsInteractor{
-		ListChangesInteractor: &listChangesInteractor{git, pipeline},
+	it := &buildProjectsInteractor{
+		ListChangesInteractor: NewListChangesInteractor(git, pipeline, presenter, config.ListChangesConfig),
 		presenter:             presenter,
 		pipeline:              pipeline,
+		maxParallel:           config.MaxParallel,
+		retries:               config.Retries,
+		keepGoing:             config.KeepGoing,
+		errorOnNoChanges:      config.ErrorOnNoChanges,
+		dryRun:                config.DryRun,
+		failureLogLines:       config.FailureLogLines,
+		timeout:               buildTimeoutDefault,
+		pollInterval:          buildPollIntervalDefault,
+		projects:              config.Projects,
+		reportCommitStatus:    config.ReportCommitStatus,
+		retryPolicy:           core.DefaultRetryPolicy,
 	}
+	if config.Timeout > 0 {
+		it.timeout = config.Timeout
+	}
+	if config.PollInterval > 0 {
+		it.pollInterval = config.PollInterval
+	}
+	return it
+}
+
+type BuildProjectsConfig struct {
+	ListChangesConfig
+
+	// maximum number of builds running at the same time, unlimited if zero
+	MaxParallel int
+	// number of times a failed build is re-triggered before it is considered failed
+	Retries int
+	// wait for all builds even if some have failed, and report a summary
+	KeepGoing bool
+	// fail with ErrNoChanges instead of succeeding when no project has changes
+	ErrorOnNoChanges bool
+	// report the build requests of changed projects instead of triggering builds
+	DryRun bool
+	// number of log lines of failed steps reported for failed builds, disabled if zero
+	// or the pipeline is not a core.BuildLogGateway
+	FailureLogLines int
+	// maximum time to wait for a build, 15 minutes if zero
+	Timeout time.Duration
+	// interval between build status checks, 15 seconds if zero
+	PollInterval time.Duration
+	// overrides for specific projects, by project name
+	Projects map[string]ProjectConfig
+	// report the status of each project on the current commit,
+	// only if the pipeline is a core.CommitStatusGateway
+	ReportCommitStatus bool
+}
+
+type ProjectConfig struct {
+	// maximum time to wait for a build of the project, BuildProjectsConfig.Timeout if zero
+	Timeout time.Duration
+	// interval between build status checks of the project, BuildProjectsConfig.PollInterval if zero
+	PollInterval time.Duration
 }
 
 type buildProjectsInteractor struct {
 	ListChangesInteractor
-	presenter BuildProjectsOutput
-	pipeline  core.PipelineGateway
+	presenter          BuildProjectsOutput
+	pipeline           core.PipelineGateway
+	maxParallel        int
+	retries            int
+	keepGoing          bool
+	errorOnNoChanges   bool
+	dryRun             bool
+	failureLogLines    int
+	timeout            time.Duration
+	pollInterval       time.Duration
+	projects           map[string]ProjectConfig
+	reportCommitStatus bool
+	retryPolicy        core.RetryPolicy
 }
 
-func (it *buildProjectsInteractor) BuildPaths(ctx context.Context, paths []string, workflowID string) {
+func (it *buildProjectsInteractor) BuildPaths(ctx context.Context, paths []string, workflowID string) error {
 	projectNames, err := it.ListProjects(ctx, paths, workflowID)
 	if err != nil {
-		it.presenter.ThrowError(errors.Wrapf(err, `can't list projects to build for workflow ID "%s"`, workflowID))
-		return
+		return errors.Wrapf(err, `can't list projects to build for workflow ID "%s"`, workflowID)
+	}
+	if len(projectNames) == 0 && it.errorOnNoChanges == true {
+		return ErrNoChanges
 	}
-	it.buildFor(ctx, projectNames)
+	if it.dryRun == true {
+		return it.planFor(projectNames)
+	}
+	it.reportUnaffected(ctx, paths, projectNames)
+	return it.buildFor(ctx, projectNames)
 }
 
-func (it *buildProjectsInteractor) BuildPathsOnce(ctx context.Context, paths []string, workflowID string) {
+func (it *buildProjectsInteractor) BuildPathsOnce(ctx context.Context, paths []string, workflowID string) error {
 	projectNamesJoined, err := it.ListProjectsJoined(ctx, paths, workflowID)
 	if err != nil {
-		it.presenter.ThrowError(errors.Wrapf(err, `can't list projects to build for workflow ID "%s"`, workflowID))
-		return
+		return errors.Wrapf(err, `can't list projects to build for workflow ID "%s"`, workflowID)
+	}
+	if projectNamesJoined == joinProjectPrefix+joinProjectPostfix && it.errorOnNoChanges == true {
+		return ErrNoChanges
 	}
-	it.buildFor(ctx, []string{projectNamesJoined})
+	if it.dryRun == true {
+		return it.planFor([]string{projectNamesJoined})
+	}
+	it.reportUnaffected(ctx, paths, projectNamesOfBuild(projectNamesJoined))
+	return it.buildFor(ctx, []string{projectNamesJoined})
 }
 
-const (
-	buildMaxSecondsDefault        = 15*time.Minute + 500*time.Millisecond
-	buildCheckAfterSecondsDefault = 15 * time.Second
-)
+// planFor reports build requests for projects without triggering them
+func (it *buildProjectsInteractor) planFor(projectNames []string) error {
+	requests := make([]*core.BuildRequest, len(projectNames))
+	for i, projectName := range projectNames {
+		req, err := it.pipeline.BuildRequestFor(projectName)
+		if err != nil {
+			return errors.Wrapf(err, `can't describe build request for project "%s"`, projectName)
+		}
+		requests[i] = req
+	}
+	it.presenter.BuildPlanned(requests)
+	return nil
+}
 
-var (
-	buildMaxSeconds        = buildMaxSecondsDefault
-	buildCheckAfterSeconds = buildCheckAfterSecondsDefault
+const (
+	buildTimeoutDefault      = 15*time.Minute + 500*time.Millisecond
+	buildPollIntervalDefault = 15 * time.Second
+	// maximum time to kill builds after the context is cancelled
+	killBuildsTimeout = 30 * time.Second
 )
 
 type buildStatus struct {
 	projectName string
 	buildID     string
-	outcome     *string
+	webURL      string
+	// nil until the first status check
+	status *core.BuildStatus
+	// number of times the build was re-triggered after failures
+	rebuilds int
+	// result of the project reported in the build summary
+	result *BuildResult
+
+	timeout      time.Duration
+	pollInterval time.Duration
+	deadline     time.Time
+	nextPollAt   time.Time
+}
+
+// started resets the timeout and polling schedule of a newly triggered build
+func (s *buildStatus) started(buildID string, webURL string) {
+	now := time.Now()
+	s.buildID = buildID
+	s.webURL = webURL
+	s.status = nil
+	s.deadline = now.Add(s.timeout)
+	s.nextPollAt = now
+	s.result.BuildID = buildID
+	s.result.WebURL = webURL
+	s.result.Outcome = BuildRunning
 }
 
-func (it *buildProjectsInteractor) buildFor(ctx context.Context, projectNames []string) {
-	statuses := make([]*buildStatus, 0)
-	for _, projectName := range projectNames {
-		buildID, err := it.pipeline.TriggerBuild(ctx, projectName)
-		// TODO: `core` package provide behaviour checking for retrying the request
+func (it *buildProjectsInteractor) buildFor(ctx context.Context, projectNames []string) error {
+	results := make([]*BuildResult, len(projectNames))
+	for i, projectName := range projectNames {
+		results[i] = &BuildResult{ProjectName: projectName, Outcome: BuildPending}
+	}
+	err := it.runBuilds(ctx, projectNames, results)
+	it.reportResults(ctx, results)
+	it.presenter.BuildSummary(results)
+	return err
+}
+
+// runBuilds triggers builds and waits for them, results are updated along the way
+func (it *buildProjectsInteractor) runBuilds(ctx context.Context, projectNames []string, results []*BuildResult) error {
+	queue := append([]*BuildResult{}, results...)
+	running := make([]*buildStatus, 0)
+	hasFailed := false
+	// a build has failed without keep-going, not finished builds are killed after this round
+	failFast := false
+
+	for {
+		// trigger queued projects while there are free slots
+		for len(queue) > 0 && !it.isAllSlotsTaken(len(running)) {
+			result := queue[0]
+			queue = queue[1:]
+
+			buildID, err := it.triggerBuild(ctx, result.ProjectName)
+			if err != nil {
+				if ctx.Err() != nil {
+					return it.cancelBuilds(running)
+				}
+				it.stopBuilds(ctx, running)
+				return err
+			}
+			if buildID == nil {
+				it.presenter.NoBuildTriggeredFor(result.ProjectName)
+				result.Outcome = BuildSkipped
+			} else {
+				webURL := it.pipeline.BuildURL(*buildID)
+				it.buildTriggered(ctx, result.ProjectName, *buildID, webURL)
+				status := &buildStatus{
+					projectName:  result.ProjectName,
+					result:       result,
+					timeout:      it.timeoutFor(result.ProjectName),
+					pollInterval: it.pollIntervalFor(result.ProjectName),
+				}
+				status.started(*buildID, webURL)
+				running = append(running, status)
+			}
+		}
+
+		if len(running) == 0 {
+			return it.finishBuilds(projectNames, results, hasFailed)
+		}
+
+		due := make([]*buildStatus, 0)
+		now := time.Now()
+		for _, s := range running {
+			if !s.nextPollAt.After(now) {
+				due = append(due, s)
+				s.nextPollAt = now.Add(s.pollInterval)
+			}
+		}
+		err := it.pollStatuses(ctx, due)
 		if err != nil {
-			it.presenter.ThrowError(errors.Wrapf(err, `can't trigger build for project "%s"`, projectName))
-			return
+			if ctx.Err() != nil {
+				return it.cancelBuilds(running)
+			}
+			return err
 		}
-		if buildID == nil {
-			it.presenter.NoBuildTriggeredFor(projectName)
-		} else {
-			it.presenter.BuildTriggeredFor(projectName, *buildID)
-			status := &buildStatus{
-				projectName: projectName,
-				buildID:     *buildID,
-				outcome:     nil,
+
+		waiting := make([]*buildStatus, 0)
+		for i, s := range running {
+			if s.status == nil || s.status.State.IsFinished() != true {
+				// queued, running or in a state not known yet
+				waiting = append(waiting, s)
+				continue
+			}
+			s.result.Duration = s.status.Duration()
+			if s.status.WebURL != "" {
+				s.result.WebURL = s.status.WebURL
 			}
-			statuses = append(statuses, status)
+			switch s.status.State {
+			case core.BuildStateSuccess:
+				it.presenter.BuildSucceededFor(s.projectName, s.buildID)
+				s.result.Outcome = BuildSucceeded
+			case core.BuildStateSkipped:
+				it.presenter.BuildSkippedFor(s.projectName)
+				s.result.Outcome = BuildSkipped
+			case core.BuildStateCancelled:
+				// cancelled on purpose, e.g., by a user, re-triggering it is not expected
+				it.presenter.BuildCancelledFor(s.projectName, s.buildID)
+				s.result.Outcome = BuildCancelled
+				hasFailed = true
+				failFast = it.keepGoing != true
+			case core.BuildStateFailed, core.BuildStateTimedOut:
+				if s.rebuilds >= it.retries || failFast == true {
+					it.buildFailed(ctx, s)
+					s.result.Outcome = BuildFailed
+					hasFailed = true
+					failFast = it.keepGoing != true
+					continue
+				}
+				// re-trigger a possibly flaky build
+				s.rebuilds++
+				it.presenter.RetryingFailedBuildFor(s.projectName, s.buildID, s.rebuilds, it.retries)
+				buildID, err := it.triggerBuild(ctx, s.projectName)
+				if err != nil {
+					if ctx.Err() != nil {
+						return it.cancelBuilds(append(waiting, running[i+1:]...))
+					}
+					it.stopBuilds(ctx, append(waiting, running[i+1:]...))
+					return err
+				}
+				if buildID == nil {
+					it.presenter.NoBuildTriggeredFor(s.projectName)
+					it.buildFailed(ctx, s)
+					s.result.Outcome = BuildFailed
+					hasFailed = true
+					failFast = it.keepGoing != true
+					continue
+				}
+				webURL := it.pipeline.BuildURL(*buildID)
+				it.buildTriggered(ctx, s.projectName, *buildID, webURL)
+				s.started(*buildID, webURL)
+				waiting = append(waiting, s)
+			}
+		}
+		running = waiting
+
+		if failFast == true {
+			// fail fast, kill all not finished builds, queued projects are not triggered
+			if len(running) > 0 {
+				it.killBuilds(ctx, running)
+			}
+			for _, s := range running {
+				s.result.Outcome = BuildCancelled
+			}
+			return ErrBuildFailed
+		}
+
+		// start next queued projects as soon as some builds are finished
+		if len(queue) > 0 && !it.isAllSlotsTaken(len(running)) {
+			continue
+		}
+		if len(running) == 0 {
+			return it.finishBuilds(projectNames, results, hasFailed)
+		}
+		if len(due) > 0 {
+			it.presenter.WaitingFor(buildInfosOf(running))
+		}
+
+		select {
+		case <-ctx.Done():
+			return it.cancelBuilds(running)
+		case <-time.After(time.Until(nextWakeUpOf(running))):
+		}
+
+		overdue, notOverdue := splitOverdue(running, time.Now())
+		if len(overdue) == 0 {
+			continue
+		}
+		reported := make(map[time.Duration]bool)
+		for _, s := range overdue {
+			if reported[s.timeout] != true {
+				it.presenter.Timeout(s.timeout)
+				reported[s.timeout] = true
+			}
+		}
+		if it.keepGoing != true {
+			// fail fast, kill all not finished builds, the ones still in time are cancelled
+			it.killBuilds(ctx, running)
+			for _, s := range overdue {
+				it.presenter.BuildTimedOutFor(s.projectName, s.buildID)
+				s.result.Outcome = BuildTimedOut
+			}
+			for _, s := range notOverdue {
+				it.presenter.BuildCancelledFor(s.projectName, s.buildID)
+				s.result.Outcome = BuildCancelled
+			}
+			return ErrBuildTimeout
+		}
+		it.killBuilds(ctx, overdue)
+		for _, s := range overdue {
+			it.presenter.BuildTimedOutFor(s.projectName, s.buildID)
+			s.result.Outcome = BuildTimedOut
+		}
+		running = notOverdue
+	}
+}
+
+// buildFailed reports a failed build, with an excerpt of its logs if possible
+func (it *buildProjectsInteractor) buildFailed(ctx context.Context, s *buildStatus) {
+	it.presenter.BuildFailedFor(s.projectName, s.buildID)
+	logs, ok := it.pipeline.(core.BuildLogGateway)
+	if ok != true || it.failureLogLines <= 0 {
+		return
+	}
+	var excerpts []*core.LogExcerpt
+	err := it.retry(ctx, fmt.Sprintf(`getting logs for build ID "%s"`, s.buildID), func() (err error) {
+		excerpts, err = logs.FailedLogTail(ctx, s.buildID, it.failureLogLines)
+		return err
+	})
+	if err != nil {
+		// logs are only informative, do not fail the build because of them
+		it.presenter.BuildLogError(s.projectName, errors.Wrapf(err, `can't get logs for build ID "%s"`, s.buildID))
+		return
+	}
+	it.presenter.BuildLogFor(s.projectName, s.buildID, excerpts)
+}
+
+func (it *buildProjectsInteractor) buildTriggered(ctx context.Context, projectName string, buildID string, webURL string) {
+	it.presenter.BuildTriggeredFor(projectName, buildID, webURL)
+	it.setProjectStatus(ctx, projectName, &core.ProjectStatus{
+		State:       core.CommitStatePending,
+		Description: "Build is running",
+		TargetURL:   webURL,
+	})
+}
+
+// reportUnaffected reports projects of paths without changes as neutral
+func (it *buildProjectsInteractor) reportUnaffected(ctx context.Context, paths []string, changedProjectNames []string) {
+	changed := make(map[string]bool, len(changedProjectNames))
+	for _, projectName := range changedProjectNames {
+		changed[projectName] = true
+	}
+	for _, path := range paths {
+		projectName := projectNameFor(path)
+		if changed[projectName] == true {
+			continue
+		}
+		it.setProjectStatus(ctx, projectName, &core.ProjectStatus{
+			State:       core.CommitStateNeutral,
+			Description: "Not affected by changes",
+		})
+	}
+}
+
+// reportResults reports the final status of each build
+func (it *buildProjectsInteractor) reportResults(ctx context.Context, results []*BuildResult) {
+	if ctx.Err() != nil {
+		// the build context is already cancelled, report with a new one
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), killBuildsTimeout)
+		defer cancel()
+	}
+	for _, r := range results {
+		if r.Outcome == BuildRunning {
+			// the build may still finish, keep its status pending instead of guessing the outcome
+			continue
+		}
+		it.setProjectStatus(ctx, r.ProjectName, projectStatusOf(r))
+	}
+}
+
+func projectStatusOf(result *BuildResult) *core.ProjectStatus {
+	status := &core.ProjectStatus{TargetURL: result.WebURL}
+	switch result.Outcome {
+	case BuildSucceeded:
+		status.State = core.CommitStateSuccess
+		status.Description = "Build succeeded"
+	case BuildSkipped:
+		status.State = core.CommitStateNeutral
+		status.Description = "Build was skipped"
+	case BuildTimedOut:
+		status.State = core.CommitStateFailure
+		status.Description = "Build was not finished in time"
+	case BuildCancelled:
+		status.State = core.CommitStateFailure
+		status.Description = "Build was cancelled"
+	case BuildFailed:
+		status.State = core.CommitStateFailure
+		status.Description = "Build failed"
+	default:
+		// not triggered, since builds are stopped before
+		status.State = core.CommitStateFailure
+		status.Description = "Build was not started"
+	}
+	return status
+}
+
+// setProjectStatus reports the status of projects built by a build on the current commit, if enabled
+func (it *buildProjectsInteractor) setProjectStatus(ctx context.Context, buildName string, status *core.ProjectStatus) {
+	statuses, ok := it.pipeline.(core.CommitStatusGateway)
+	if ok != true || it.reportCommitStatus != true {
+		return
+	}
+	for _, projectName := range projectNamesOfBuild(buildName) {
+		err := it.retry(ctx, fmt.Sprintf(`setting status for project "%s"`, projectName), func() error {
+			return statuses.SetProjectStatus(ctx, projectName, status)
+		})
+		if err != nil {
+			// statuses are only informative, do not fail the build because of them
+			it.presenter.CommitStatusError(projectName, errors.Wrapf(err, `can't set status for project "%s"`, projectName))
 		}
 	}
+}
 
+// projectNamesOfBuild returns names of projects built by a build, i.e., joined ones for BuildPathsOnce
+func projectNamesOfBuild(buildName string) []string {
+	if !strings.HasPrefix(buildName, joinProjectPrefix) || !strings.HasSuffix(buildName, joinProjectPostfix) {
+		return []string{buildName}
+	}
+	joined := strings.TrimSuffix(strings.TrimPrefix(buildName, joinProjectPrefix), joinProjectPostfix)
+	if joined == "" {
+		return []string{}
+	}
+	return strings.Split(joined, joinProjectSeparater)
+}
+
+// cancelBuilds kills not finished builds after the context is cancelled
+func (it *buildProjectsInteractor) cancelBuilds(statuses []*buildStatus) error {
+	it.presenter.Cancelled()
+	if len(statuses) > 0 {
+		// the build context is already cancelled, kill builds with a new one
+		ctx, cancel := context.WithTimeout(context.Background(), killBuildsTimeout)
+		defer cancel()
+		it.killBuilds(ctx, statuses)
+	}
+	for _, s := range statuses {
+		s.result.Outcome = BuildCancelled
+	}
+	return ErrBuildCancelled
+}
+
+// stopBuilds kills not finished builds after an error, they are not watched anymore
+func (it *buildProjectsInteractor) stopBuilds(ctx context.Context, statuses []*buildStatus) {
 	if len(statuses) == 0 {
-		it.presenter.AllBuildSucceeded(projectNames)
 		return
 	}
+	it.killBuilds(ctx, statuses)
+	for _, s := range statuses {
+		it.presenter.BuildCancelledFor(s.projectName, s.buildID)
+		s.result.Outcome = BuildCancelled
+	}
+}
 
-	ticker := time.NewTicker(buildCheckAfterSeconds)
-	done := make(chan bool)
+func (it *buildProjectsInteractor) killBuilds(ctx context.Context, statuses []*buildStatus) {
+	it.presenter.KillingBuilds(buildInfosOf(statuses))
+	for _, s := range statuses {
+		// kill unfinished build
+		err := it.pipeline.KillBuild(ctx, s.buildID)
+		if err != nil {
+			it.presenter.KillBuildError(
+				s.projectName,
+				errors.Wrapf(err, `can't kill build ID "%s"`, s.buildID),
+			)
+			// do not return here, try to kill all build
+		}
+	}
+	it.presenter.NotFinishedBuildsKilled()
+}
 
-	defer func() {
-		ticker.Stop()
-		close(done)
-	}()
+// timeoutFor returns the timeout of a build, the largest one of its projects for a joined build
+func (it *buildProjectsInteractor) timeoutFor(buildName string) time.Duration {
+	timeout := time.Duration(0)
+	for _, projectName := range projectNamesOfBuild(buildName) {
+		projectTimeout := it.timeout
+		if p, ok := it.projects[projectName]; ok && p.Timeout > 0 {
+			projectTimeout = p.Timeout
+		}
+		if projectTimeout > timeout {
+			timeout = projectTimeout
+		}
+	}
+	if timeout == 0 {
+		return it.timeout
+	}
+	return timeout
+}
 
-	go func() {
-		select {
-		case <-done:
-			return
-		case <-time.After(buildMaxSeconds):
-			done <- true
+// pollIntervalFor returns the poll interval of a build, the smallest one of its projects for a joined build
+func (it *buildProjectsInteractor) pollIntervalFor(buildName string) time.Duration {
+	interval := time.Duration(0)
+	for _, projectName := range projectNamesOfBuild(buildName) {
+		projectInterval := it.pollInterval
+		if p, ok := it.projects[projectName]; ok && p.PollInterval > 0 {
+			projectInterval = p.PollInterval
+		}
+		if interval == 0 || projectInterval < interval {
+			interval = projectInterval
 		}
-	}()
+	}
+	if interval == 0 {
+		return it.pollInterval
+	}
+	return interval
+}
 
-	var waitingList []*BuildInfo
-loop:
-	for {
-		waitingList = make([]*BuildInfo, 0)
-		for _, s := range statuses {
-			if s.outcome == nil {
-				outcome, err := it.pipeline.BuildStatus(ctx, s.buildID)
-				// TODO: `core` package provide behaviour checking for retrying the request
-				if err != nil {
-					it.presenter.ThrowError(errors.Wrapf(err, `can't get build status for build ID "%s"`, s.buildID))
-					return
-				}
-				s.outcome = outcome
-
-				if s.outcome != nil {
-					switch *s.outcome {
-					case "success":
-					case "skipped":
-						it.presenter.BuildSkippedFor(s.projectName)
-					case "failed":
-						it.presenter.BuildFailedFor(s.projectName, s.buildID)
-						return
-					default:
-						panic("unknown build status, this should not occur")
-					}
-				} else {
-					info := BuildInfo{ProjectName: s.projectName, BuildID: s.buildID}
-					waitingList = append(waitingList, &info)
-				}
-			}
+// nextWakeUpOf returns the earliest time some builds should be polled or are timed out
+func nextWakeUpOf(statuses []*buildStatus) time.Time {
+	next := statuses[0].nextPollAt
+	for _, s := range statuses {
+		if s.nextPollAt.Before(next) {
+			next = s.nextPollAt
+		}
+		if s.deadline.Before(next) {
+			next = s.deadline
 		}
-		if len(waitingList) > 0 {
-			it.presenter.WaitingFor(waitingList)
+	}
+	return next
+}
+
+func splitOverdue(statuses []*buildStatus, now time.Time) (overdue []*buildStatus, notOverdue []*buildStatus) {
+	overdue = make([]*buildStatus, 0)
+	notOverdue = make([]*buildStatus, 0)
+	for _, s := range statuses {
+		if s.deadline.After(now) {
+			notOverdue = append(notOverdue, s)
 		} else {
-			it.presenter.AllBuildSucceeded(projectNames)
-			return
+			overdue = append(overdue, s)
 		}
-		select {
-		case <-done:
-			break loop
-		case <-ticker.C:
-			continue
+	}
+	return overdue, notOverdue
+}
+
+// finishBuilds reports the outcome after all builds are finished
+func (it *buildProjectsInteractor) finishBuilds(projectNames []string, results []*BuildResult, hasFailed bool) error {
+	hasTimedOut := false
+	for _, r := range results {
+		if r.Outcome == BuildTimedOut {
+			hasTimedOut = true
 		}
 	}
+	if hasFailed != true && hasTimedOut != true {
+		it.presenter.AllBuildSucceeded(projectNames)
+	}
+	if hasFailed == true {
+		return ErrBuildFailed
+	}
+	if hasTimedOut == true {
+		return ErrBuildTimeout
+	}
+	return nil
+}
 
-	it.presenter.Timeout(buildMaxSeconds)
-	it.presenter.KillingBuilds(waitingList)
-	for _, s := range statuses {
-		if s.outcome == nil {
-			// kill unfinished build
-			err := it.pipeline.KillBuild(ctx, s.buildID)
+func (it *buildProjectsInteractor) triggerBuild(ctx context.Context, projectName string) (*string, error) {
+	var buildID *string
+	err := it.retry(ctx, fmt.Sprintf(`triggering build for project "%s"`, projectName), func() (err error) {
+		buildID, err = it.pipeline.TriggerBuild(ctx, projectName)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(&APIError{Err: err}, `can't trigger build for project "%s"`, projectName)
+	}
+	return buildID, nil
+}
+
+func (it *buildProjectsInteractor) isAllSlotsTaken(running int) bool {
+	return it.maxParallel > 0 && running >= it.maxParallel
+}
+
+// pollStatuses gets build statuses concurrently, and updates given statuses.
+// Presenters are not required to be goroutine-safe, so retries are reported after all requests are done.
+func (it *buildProjectsInteractor) pollStatuses(ctx context.Context, statuses []*buildStatus) error {
+	errs := make([]error, len(statuses))
+	retries := make([][]retryEvent, len(statuses))
+	var wg sync.WaitGroup
+	for i, s := range statuses {
+		wg.Add(1)
+		go func(i int, s *buildStatus) {
+			defer wg.Done()
+			var status *core.BuildStatus
+			request := fmt.Sprintf(`getting build status for build ID "%s"`, s.buildID)
+			err := it.retryWith(ctx, request, func() (err error) {
+				status, err = it.pipeline.BuildStatus(ctx, s.buildID)
+				return err
+			}, func(e retryEvent) {
+				retries[i] = append(retries[i], e)
+			})
 			if err != nil {
-				it.presenter.KillBuildError(
-					s.projectName,
-					errors.Wrapf(err, `can't kill build ID "%s"`, s.buildID),
-				)
-				// do not return here, try to kill all build
+				errs[i] = errors.Wrapf(&APIError{Err: err}, `can't get build status for build ID "%s"`, s.buildID)
+				return
 			}
+			s.status = status
+		}(i, s)
+	}
+	wg.Wait()
+
+	for _, events := range retries {
+		for _, e := range events {
+			it.presenter.RetryingRequest(e.request, e.attempt, e.delay, e.err)
 		}
 	}
-	it.presenter.NotFinishedBuildsKilled()
-	return
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retryEvent is a failed attempt of a request which is retried
+type retryEvent struct {
+	request string
+	attempt int
+	delay   time.Duration
+	err     error
+}
+
+// retry calls the given request until it succeeds, or fails with an error which is not retryable,
+// or the retry policy is exhausted
+func (it *buildProjectsInteractor) retry(ctx context.Context, request string, call func() error) error {
+	return it.retryWith(ctx, request, call, func(e retryEvent) {
+		it.presenter.RetryingRequest(e.request, e.attempt, e.delay, e.err)
+	})
+}
+
+// retryWith is retry reporting each retry to the given function instead of the presenter
+func (it *buildProjectsInteractor) retryWith(
+	ctx context.Context,
+	request string,
+	call func() error,
+	retrying func(retryEvent),
+) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil {
+			return nil
+		}
+		retryable, wait := it.pipeline.IsRetryable(err)
+		if retryable != true || attempt > it.retryPolicy.MaxRetries {
+			return err
+		}
+		delay := it.retryPolicy.Backoff(attempt)
+		if wait > delay {
+			// honour the time the server asks to wait
+			delay = wait
+		}
+		retrying(retryEvent{request: request, attempt: attempt, delay: delay, err: err})
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "stop retrying %s", request)
+		case <-time.After(delay):
+		}
+	}
+}
+
+func buildInfosOf(statuses []*buildStatus) []*BuildInfo {
+	infos := make([]*BuildInfo, len(statuses))
+	for i, s := range statuses {
+		infos[i] = &BuildInfo{ProjectName: s.projectName, BuildID: s.buildID, WebURL: s.webURL}
+	}
+	return infos
 }
@@ -2,14 +2,17 @@ package interactor_impl
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
 	mock_core "github.com/whatthefar/monorepo-toolkit/pkg/core/mock"
 	. "github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 	mock_interactor "github.com/whatthefar/monorepo-toolkit/pkg/interactor/mock"
@@ -23,7 +26,8 @@ func TestNewBuildProjectsInteractor(t *testing.T) {
 	git := mock_core.NewMockGitGateway(ctrl)
 	pipeline := mock_core.NewMockPipelineGateway(ctrl)
 	presenter := mock_interactor.NewMockBuildProjectsOutput(ctrl)
-	interactor := NewBuildProjectsInteractor(git, pipeline, presenter)
+	config := BuildProjectsConfig{MaxParallel: 2}
+	interactor := NewBuildProjectsInteractor(git, pipeline, presenter, config)
 
 	assert.Implements(t, (*BuildProjectsInteractor)(nil), interactor)
 	assert.IsType(t, new(buildProjectsInteractor), interactor)
@@ -36,6 +40,10 @@ func TestNewBuildProjectsInteractor(t *testing.T) {
 	assert.Equal(t, presenter, impl.presenter)
 	assert.NotNil(t, impl.pipeline)
 	assert.Equal(t, pipeline, impl.pipeline)
+	assert.Equal(t, config.MaxParallel, impl.maxParallel)
+	// zero durations fallback to defaults
+	assert.Equal(t, buildTimeoutDefault, impl.timeout)
+	assert.Equal(t, buildPollIntervalDefault, impl.pollInterval)
 }
 
 func TestBuildProjectsInteractor(t *testing.T) {
@@ -51,11 +59,146 @@ func TestBuildProjectsInteractor(t *testing.T) {
 			ListChangesInteractor: listChangesUc,
 			presenter:             presenter,
 			pipeline:              pipeline,
+			timeout:               buildTimeoutDefault,
+			pollInterval:          buildPollIntervalDefault,
+			retryPolicy: core.RetryPolicy{
+				MaxRetries: 1,
+				BaseDelay:  time.Millisecond,
+				MaxDelay:   time.Millisecond,
+			},
 		}
 
-		// reset constant to default value
-		buildMaxSeconds = buildMaxSecondsDefault
-		buildCheckAfterSeconds = buildCheckAfterSecondsDefault
+		Convey("Mock a ListChanges func, with no project has changes", func() {
+			paths := []string{"services/app3"}
+			workflowID := "main.yml"
+			ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+			listChangesUc.EXPECT().
+				ListProjects(
+					gomock.AssignableToTypeOf(ctxType),
+					gomock.Eq(paths),
+					gomock.Eq(workflowID),
+				).
+				Return([]string{}, nil)
+
+			Convey("Setup error on no changes", func() {
+				interactor.errorOnNoChanges = true
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("It should return ErrNoChanges without triggering builds", func() {
+						So(err, ShouldEqual, ErrNoChanges)
+						ctrl.Finish()
+					})
+				})
+			})
+		})
+
+		// no web URL by default
+		pipeline.EXPECT().BuildURL(gomock.Any()).Return("").AnyTimes()
+
+		Convey("Mock a ListChanges func, with a project not affected by changes", func() {
+			paths := []string{"services/app1", "services/app3"}
+			workflowID := "main.yml"
+			ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+			listChangesUc.EXPECT().
+				ListProjects(
+					gomock.AssignableToTypeOf(ctxType),
+					gomock.Eq(paths),
+					gomock.Eq(workflowID),
+				).
+				Return([]string{"app1"}, nil)
+
+			Convey("Setup commit statuses, with a pipeline reporting them", func() {
+				statuses := mock_core.NewMockCommitStatusGateway(ctrl)
+				interactor.pipeline = &pipelineWithStatuses{pipeline, statuses}
+				interactor.reportCommitStatus = true
+
+				statusErr := errors.New("resource not accessible by integration")
+				gomock.InOrder(
+					statuses.EXPECT().
+						SetProjectStatus(gomock.AssignableToTypeOf(ctxType), "app3", &core.ProjectStatus{
+							State:       core.CommitStateNeutral,
+							Description: "Not affected by changes",
+						}).
+						Return(nil),
+					pipeline.EXPECT().
+						TriggerBuild(gomock.AssignableToTypeOf(ctxType), gomock.Eq("app1")).
+						Return(utils.StrAddr("111"), nil),
+					presenter.EXPECT().BuildTriggeredFor("app1", "111", ""),
+					statuses.EXPECT().
+						SetProjectStatus(gomock.AssignableToTypeOf(ctxType), "app1", &core.ProjectStatus{
+							State:       core.CommitStatePending,
+							Description: "Build is running",
+						}).
+						Return(statusErr),
+					presenter.EXPECT().CommitStatusError("app1", gomock.Any()),
+					pipeline.EXPECT().
+						BuildStatus(gomock.AssignableToTypeOf(ctxType), gomock.Eq("111")).
+						Return(statusOf(core.BuildStateSuccess), nil),
+					presenter.EXPECT().BuildSucceededFor("app1", "111"),
+					presenter.EXPECT().AllBuildSucceeded([]string{"app1"}),
+					statuses.EXPECT().
+						SetProjectStatus(gomock.AssignableToTypeOf(ctxType), "app1", &core.ProjectStatus{
+							State:       core.CommitStateSuccess,
+							Description: "Build succeeded",
+						}).
+						Return(nil),
+					presenter.EXPECT().BuildSummary(gomock.Any()),
+				)
+				pipeline.EXPECT().IsRetryable(statusErr).Return(false, time.Duration(0))
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("It should report statuses, and not fail because of them", func() {
+						So(err, ShouldBeNil)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup commit statuses, with a build not waited for after an error", func() {
+				statuses := mock_core.NewMockCommitStatusGateway(ctrl)
+				interactor.pipeline = &pipelineWithStatuses{pipeline, statuses}
+				interactor.reportCommitStatus = true
+
+				apiErr := errors.New("404 Not Found")
+				gomock.InOrder(
+					statuses.EXPECT().
+						SetProjectStatus(gomock.AssignableToTypeOf(ctxType), "app3", gomock.Any()).
+						Return(nil),
+					pipeline.EXPECT().
+						TriggerBuild(gomock.AssignableToTypeOf(ctxType), gomock.Eq("app1")).
+						Return(utils.StrAddr("111"), nil),
+					presenter.EXPECT().BuildTriggeredFor("app1", "111", ""),
+					statuses.EXPECT().
+						SetProjectStatus(gomock.AssignableToTypeOf(ctxType), "app1", &core.ProjectStatus{
+							State:       core.CommitStatePending,
+							Description: "Build is running",
+						}).
+						Return(nil),
+					pipeline.EXPECT().
+						BuildStatus(gomock.AssignableToTypeOf(ctxType), gomock.Eq("111")).
+						Return(nil, apiErr),
+					presenter.EXPECT().BuildSummary([]*BuildResult{
+						{ProjectName: "app1", BuildID: "111", Outcome: BuildRunning},
+					}),
+				)
+				pipeline.EXPECT().IsRetryable(apiErr).Return(false, time.Duration(0))
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("It should keep the status of the running build pending", func() {
+						So(errors.Cause(err), ShouldResemble, &APIError{Err: apiErr})
+						ctrl.Finish()
+					})
+				})
+			})
+		})
 
 		Convey("Mock a ListChanges func", func() {
 			paths := []string{"services/app1", "services/app2"}
@@ -81,17 +224,107 @@ func TestBuildProjectsInteractor(t *testing.T) {
 							gomock.Eq(name),
 						).
 						Return(utils.StrAddr(buildID), nil)
-					presenter.EXPECT().BuildTriggeredFor(name, buildID).Return()
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+
+					pipeline.EXPECT().
+						BuildStatus(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildID),
+						).
+						Return(statusOf(core.BuildStateSuccess), nil)
+					presenter.EXPECT().BuildSucceededFor(name, buildID)
+				}
+				presenter.EXPECT().AllBuildSucceeded(projectNames)
+
+				presenter.EXPECT().BuildSummary(gomock.Any())
+
+				Convey("When BuildFor is called", func() {
+					interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup successful builds, one at a time", func() {
+				interactor.maxParallel = 1
+
+				calls := make([]*gomock.Call, 0)
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					calls = append(calls,
+						pipeline.EXPECT().
+							TriggerBuild(
+								gomock.AssignableToTypeOf(ctxType),
+								gomock.Eq(name),
+							).
+							Return(utils.StrAddr(buildID), nil),
+						presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return(),
+						pipeline.EXPECT().
+							BuildStatus(
+								gomock.AssignableToTypeOf(ctxType),
+								gomock.Eq(buildID),
+							).
+							Return(statusOf(core.BuildStateSuccess), nil),
+						presenter.EXPECT().BuildSucceededFor(name, buildID),
+					)
+				}
+				// the second build should be triggered only after the first one is finished
+				gomock.InOrder(calls...)
+				presenter.EXPECT().AllBuildSucceeded(projectNames)
+
+				presenter.EXPECT().BuildSummary(gomock.Any())
+
+				Convey("When BuildFor is called", func() {
+					interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup requests to fail with retryable errors once", func() {
+				transientErr := errors.New("502 Bad Gateway")
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					gomock.InOrder(
+						pipeline.EXPECT().
+							TriggerBuild(
+								gomock.AssignableToTypeOf(ctxType),
+								gomock.Eq(name),
+							).
+							Return(nil, transientErr),
+						presenter.EXPECT().
+							RetryingRequest(
+								fmt.Sprintf(`triggering build for project "%s"`, name),
+								1,
+								gomock.Any(),
+								transientErr,
+							),
+						pipeline.EXPECT().
+							TriggerBuild(
+								gomock.AssignableToTypeOf(ctxType),
+								gomock.Eq(name),
+							).
+							Return(utils.StrAddr(buildID), nil),
+					)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
 
 					pipeline.EXPECT().
 						BuildStatus(
 							gomock.AssignableToTypeOf(ctxType),
 							gomock.Eq(buildID),
 						).
-						Return(utils.StrAddr("success"), nil)
+						Return(statusOf(core.BuildStateSuccess), nil)
+					presenter.EXPECT().BuildSucceededFor(name, buildID)
 				}
+				pipeline.EXPECT().IsRetryable(transientErr).Return(true, time.Duration(0)).Times(2)
 				presenter.EXPECT().AllBuildSucceeded(projectNames)
 
+				presenter.EXPECT().BuildSummary(gomock.Any())
+
 				Convey("When BuildFor is called", func() {
 					interactor.BuildPaths(ctx, paths, workflowID)
 
@@ -101,6 +334,129 @@ func TestBuildProjectsInteractor(t *testing.T) {
 				})
 			})
 
+			Convey("Setup status checks to fail with retryable errors once", func() {
+				transientErr := errors.New("502 Bad Gateway")
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+
+					gomock.InOrder(
+						pipeline.EXPECT().
+							BuildStatus(
+								gomock.AssignableToTypeOf(ctxType),
+								gomock.Eq(buildID),
+							).
+							Return(nil, transientErr),
+						pipeline.EXPECT().
+							BuildStatus(
+								gomock.AssignableToTypeOf(ctxType),
+								gomock.Eq(buildID),
+							).
+							Return(statusOf(core.BuildStateSuccess), nil),
+						presenter.EXPECT().BuildSucceededFor(name, buildID),
+					)
+					presenter.EXPECT().
+						RetryingRequest(
+							fmt.Sprintf(`getting build status for build ID "%s"`, buildID),
+							1,
+							gomock.Any(),
+							transientErr,
+						)
+				}
+				pipeline.EXPECT().IsRetryable(transientErr).Return(true, time.Duration(0)).Times(2)
+				presenter.EXPECT().AllBuildSucceeded(projectNames)
+
+				presenter.EXPECT().BuildSummary(gomock.Any())
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("It should report retries of concurrent status checks", func() {
+						So(err, ShouldBeNil)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup a request to fail with a retryable error until the retry policy is exhausted", func() {
+				transientErr := errors.New("502 Bad Gateway")
+				pipeline.EXPECT().
+					TriggerBuild(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(projectNames[0]),
+					).
+					Return(nil, transientErr).
+					Times(2)
+				pipeline.EXPECT().IsRetryable(transientErr).Return(true, time.Duration(0)).Times(2)
+				presenter.EXPECT().RetryingRequest(gomock.Any(), 1, gomock.Any(), transientErr)
+
+				// no build has been triggered
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], Outcome: BuildPending},
+					{ProjectName: projectNames[1], Outcome: BuildPending},
+				})
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("It should return the error", func() {
+						So(err, ShouldNotBeNil)
+						So(errors.Cause(err), ShouldResemble, &APIError{Err: transientErr})
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup second build to fail to be triggered", func() {
+				pipeline.EXPECT().
+					TriggerBuild(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(projectNames[0]),
+					).
+					Return(utils.StrAddr(buildIDs[0]), nil)
+				presenter.EXPECT().BuildTriggeredFor(projectNames[0], buildIDs[0], "").Return()
+				triggerErr := errors.New("run not found")
+				pipeline.EXPECT().
+					TriggerBuild(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(projectNames[1]),
+					).
+					Return(nil, triggerErr)
+				pipeline.EXPECT().IsRetryable(triggerErr).Return(false, time.Duration(0))
+
+				// it should kill the build already triggered
+				infos := []*BuildInfo{{ProjectName: projectNames[0], BuildID: buildIDs[0]}}
+				presenter.EXPECT().KillingBuilds(infos).Return()
+				pipeline.EXPECT().
+					KillBuild(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[0]),
+					).
+					Return(nil)
+				presenter.EXPECT().NotFinishedBuildsKilled().Return()
+				presenter.EXPECT().BuildCancelledFor(projectNames[0], buildIDs[0])
+
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildCancelled},
+					{ProjectName: projectNames[1], Outcome: BuildPending},
+				})
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("It should return the error", func() {
+						So(errors.Cause(err), ShouldResemble, &APIError{Err: triggerErr})
+						ctrl.Finish()
+					})
+				})
+			})
+
 			Convey("Setup second build to fail", func() {
 				for i, name := range projectNames {
 					buildID := buildIDs[i]
@@ -110,7 +466,7 @@ func TestBuildProjectsInteractor(t *testing.T) {
 							gomock.Eq(name),
 						).
 						Return(utils.StrAddr(buildID), nil)
-					presenter.EXPECT().BuildTriggeredFor(name, buildID).Return()
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
 
 					if i == 1 {
 						// fail build status
@@ -119,7 +475,7 @@ func TestBuildProjectsInteractor(t *testing.T) {
 								gomock.AssignableToTypeOf(ctxType),
 								gomock.Eq(buildID),
 							).
-							Return(utils.StrAddr("failed"), nil)
+							Return(statusOf(core.BuildStateFailed), nil)
 						presenter.EXPECT().BuildFailedFor(name, buildID)
 					} else {
 						// success build status
@@ -128,9 +484,282 @@ func TestBuildProjectsInteractor(t *testing.T) {
 								gomock.AssignableToTypeOf(ctxType),
 								gomock.Eq(buildID),
 							).
-							Return(utils.StrAddr("success"), nil)
+							Return(statusOf(core.BuildStateSuccess), nil)
+						presenter.EXPECT().BuildSucceededFor(name, buildID)
+					}
+				}
+
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildSucceeded},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1], Outcome: BuildFailed},
+				})
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildFailed)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup first build to fail, with a pipeline reading logs", func() {
+				logs := mock_core.NewMockBuildLogGateway(ctrl)
+				interactor.pipeline = &pipelineWithLogs{pipeline, logs}
+				interactor.failureLogLines = 2
+
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+				}
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[0]),
+					).
+					Return(statusOf(core.BuildStateFailed), nil)
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[1]),
+					).
+					Return(statusOf(core.BuildStateRunning), nil)
+				excerpts := []*core.LogExcerpt{
+					{Job: "build", Step: "Run tests", Lines: []string{"--- FAIL: TestApp", "FAIL"}},
+				}
+				gomock.InOrder(
+					presenter.EXPECT().BuildFailedFor(projectNames[0], buildIDs[0]),
+					logs.EXPECT().
+						FailedLogTail(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildIDs[0]),
+							gomock.Eq(2),
+						).
+						Return(excerpts, nil),
+					presenter.EXPECT().BuildLogFor(projectNames[0], buildIDs[0], excerpts),
+				)
+
+				// fail fast, the second build is killed instead of waited for
+				gomock.InOrder(
+					presenter.EXPECT().KillingBuilds([]*BuildInfo{
+						{ProjectName: projectNames[1], BuildID: buildIDs[1]},
+					}),
+					pipeline.EXPECT().
+						KillBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildIDs[1]),
+						).
+						Return(nil),
+					presenter.EXPECT().NotFinishedBuildsKilled(),
+				)
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildFailed},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1], Outcome: BuildCancelled},
+				})
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildFailed)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup first build to fail, and keep going", func() {
+				interactor.keepGoing = true
+
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+				}
+				// fail build status
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[0]),
+					).
+					Return(statusOf(core.BuildStateFailed), nil)
+				presenter.EXPECT().BuildFailedFor(projectNames[0], buildIDs[0])
+				// the other build should still be waited for
+				gomock.InOrder(
+					pipeline.EXPECT().
+						BuildStatus(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildIDs[1]),
+						).
+						Return(statusOf(core.BuildStateRunning), nil),
+					presenter.EXPECT().
+						WaitingFor([]*BuildInfo{{ProjectName: projectNames[1], BuildID: buildIDs[1]}}),
+					pipeline.EXPECT().
+						BuildStatus(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildIDs[1]),
+						).
+						Return(statusOf(core.BuildStateSuccess), nil),
+					presenter.EXPECT().BuildSucceededFor(projectNames[1], buildIDs[1]),
+				)
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildFailed},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1], Outcome: BuildSucceeded},
+				})
+
+				// speed up the next status check
+				interactor.pollInterval = 10 * time.Millisecond
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildFailed)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup first build to be cancelled, and keep going with retries", func() {
+				interactor.keepGoing = true
+				// cancelled builds should not be retried
+				interactor.retries = 1
+				startedAt := time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC)
+
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+				}
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[0]),
+					).
+					Return(statusOf(core.BuildStateCancelled), nil)
+				presenter.EXPECT().BuildCancelledFor(projectNames[0], buildIDs[0])
+				gomock.InOrder(
+					pipeline.EXPECT().
+						BuildStatus(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildIDs[1]),
+						).
+						Return(statusOf(core.BuildStateQueued), nil),
+					presenter.EXPECT().
+						WaitingFor([]*BuildInfo{{ProjectName: projectNames[1], BuildID: buildIDs[1]}}),
+					pipeline.EXPECT().
+						BuildStatus(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildIDs[1]),
+						).
+						Return(&core.BuildStatus{
+							State:      core.BuildStateSuccess,
+							StartedAt:  startedAt,
+							FinishedAt: startedAt.Add(time.Minute),
+							WebURL:     "https://example.com/runs/222",
+						}, nil),
+					presenter.EXPECT().BuildSucceededFor(projectNames[1], buildIDs[1]),
+				)
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildCancelled},
+					{
+						ProjectName: projectNames[1],
+						BuildID:     buildIDs[1],
+						Outcome:     BuildSucceeded,
+						Duration:    time.Minute,
+						WebURL:      "https://example.com/runs/222",
+					},
+				})
+
+				// speed up the next status check
+				interactor.pollInterval = 10 * time.Millisecond
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildFailed)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup second build to fail once, and retry failed builds", func() {
+				interactor.retries = 1
+				retriedBuildID := "333"
+
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+
+					if i == 1 {
+						gomock.InOrder(
+							// fail build status
+							pipeline.EXPECT().
+								BuildStatus(
+									gomock.AssignableToTypeOf(ctxType),
+									gomock.Eq(buildID),
+								).
+								Return(statusOf(core.BuildStateFailed), nil),
+							presenter.EXPECT().RetryingFailedBuildFor(name, buildID, 1, 1),
+							// re-trigger the failed build
+							pipeline.EXPECT().
+								TriggerBuild(
+									gomock.AssignableToTypeOf(ctxType),
+									gomock.Eq(name),
+								).
+								Return(utils.StrAddr(retriedBuildID), nil),
+							presenter.EXPECT().BuildTriggeredFor(name, retriedBuildID, "").Return(),
+							pipeline.EXPECT().
+								BuildStatus(
+									gomock.AssignableToTypeOf(ctxType),
+									gomock.Eq(retriedBuildID),
+								).
+								Return(statusOf(core.BuildStateSuccess), nil),
+							presenter.EXPECT().BuildSucceededFor(name, retriedBuildID),
+						)
+					} else {
+						// success build status
+						pipeline.EXPECT().
+							BuildStatus(
+								gomock.AssignableToTypeOf(ctxType),
+								gomock.Eq(buildID),
+							).
+							Return(statusOf(core.BuildStateSuccess), nil)
+						presenter.EXPECT().BuildSucceededFor(name, buildID)
 					}
 				}
+				presenter.EXPECT().
+					WaitingFor([]*BuildInfo{{ProjectName: projectNames[1], BuildID: retriedBuildID}}).
+					Return()
+				presenter.EXPECT().AllBuildSucceeded(projectNames)
+
+				// speed up the next status check
+				interactor.pollInterval = 10 * time.Millisecond
+
+				presenter.EXPECT().BuildSummary(gomock.Any())
 
 				Convey("When BuildFor is called", func() {
 					interactor.BuildPaths(ctx, paths, workflowID)
@@ -154,6 +783,11 @@ func TestBuildProjectsInteractor(t *testing.T) {
 				}
 				presenter.EXPECT().AllBuildSucceeded(projectNames).Return()
 
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], Outcome: BuildSkipped},
+					{ProjectName: projectNames[1], Outcome: BuildSkipped},
+				})
+
 				Convey("When BuildFor is called", func() {
 					interactor.BuildPaths(ctx, paths, workflowID)
 
@@ -163,9 +797,212 @@ func TestBuildProjectsInteractor(t *testing.T) {
 				})
 			})
 
+			Convey("Setup a shorter timeout for second project, and keep going", func() {
+				interactor.keepGoing = true
+				interactor.projects = map[string]ProjectConfig{
+					projectNames[1]: {Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond},
+				}
+
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+				}
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[0]),
+					).
+					Return(statusOf(core.BuildStateSuccess), nil)
+				presenter.EXPECT().BuildSucceededFor(projectNames[0], buildIDs[0])
+				// never finished
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[1]),
+					).
+					Return(statusOf(core.BuildStateRunning), nil).
+					MinTimes(1)
+				infos := []*BuildInfo{{ProjectName: projectNames[1], BuildID: buildIDs[1]}}
+				presenter.EXPECT().WaitingFor(infos).Return().MinTimes(1)
+				// only the second build should timeout
+				presenter.EXPECT().Timeout(50 * time.Millisecond).Return()
+				presenter.EXPECT().KillingBuilds(infos).Return()
+				pipeline.EXPECT().
+					KillBuild(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[1]),
+					).
+					Return(nil)
+				presenter.EXPECT().NotFinishedBuildsKilled().Return()
+				presenter.EXPECT().BuildTimedOutFor(projectNames[1], buildIDs[1])
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildSucceeded},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1], Outcome: BuildTimedOut},
+				})
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildTimeout)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup a shorter timeout for second project", func() {
+				interactor.projects = map[string]ProjectConfig{
+					projectNames[1]: {Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond},
+				}
+
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+					// never finished
+					pipeline.EXPECT().
+						BuildStatus(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildID),
+						).
+						Return(statusOf(core.BuildStateRunning), nil).
+						MinTimes(1)
+				}
+				infos := []*BuildInfo{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0]},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1]},
+				}
+				presenter.EXPECT().WaitingFor(infos).Return().MinTimes(1)
+				// only the second build should timeout
+				presenter.EXPECT().Timeout(50 * time.Millisecond).Return()
+				// it should kill all running builds
+				presenter.EXPECT().KillingBuilds(infos).Return()
+				for _, buildID := range buildIDs {
+					pipeline.EXPECT().
+						KillBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(buildID),
+						).
+						Return(nil)
+				}
+				presenter.EXPECT().NotFinishedBuildsKilled().Return()
+				// the first build is still in time, it is cancelled instead
+				presenter.EXPECT().BuildCancelledFor(projectNames[0], buildIDs[0])
+				presenter.EXPECT().BuildTimedOutFor(projectNames[1], buildIDs[1])
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildCancelled},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1], Outcome: BuildTimedOut},
+				})
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildTimeout)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup cancel while waiting for second build", func() {
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+
+				for i, name := range projectNames {
+					buildID := buildIDs[i]
+					pipeline.EXPECT().
+						TriggerBuild(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(name),
+						).
+						Return(utils.StrAddr(buildID), nil)
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
+				}
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[0]),
+					).
+					Return(statusOf(core.BuildStateSuccess), nil)
+				presenter.EXPECT().BuildSucceededFor(projectNames[0], buildIDs[0])
+				pipeline.EXPECT().
+					BuildStatus(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[1]),
+					).
+					Return(statusOf(core.BuildStateRunning), nil)
+				infos := []*BuildInfo{{ProjectName: projectNames[1], BuildID: buildIDs[1]}}
+				// e.g., SIGINT is received
+				presenter.EXPECT().WaitingFor(infos).Do(func(_ []*BuildInfo) { cancel() })
+
+				// it should kill all running builds
+				presenter.EXPECT().Cancelled().Return()
+				presenter.EXPECT().KillingBuilds(infos).Return()
+				pipeline.EXPECT().
+					KillBuild(
+						gomock.AssignableToTypeOf(ctxType),
+						gomock.Eq(buildIDs[1]),
+					).
+					DoAndReturn(func(ctx context.Context, _ string) error {
+						// builds should be killed with a live context
+						return ctx.Err()
+					})
+				presenter.EXPECT().NotFinishedBuildsKilled().Return()
+
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildSucceeded},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1], Outcome: BuildCancelled},
+				})
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildCancelled)
+						ctrl.Finish()
+					})
+				})
+			})
+
+			Convey("Setup dry run", func() {
+				interactor.dryRun = true
+
+				requests := make([]*core.BuildRequest, len(projectNames))
+				for i, name := range projectNames {
+					requests[i] = &core.BuildRequest{
+						ProjectName: name,
+						Event:       "build-" + name,
+						Payload:     fmt.Sprintf(`{"job":"%s"}`, name),
+					}
+					pipeline.EXPECT().BuildRequestFor(name).Return(requests[i], nil)
+				}
+				// no build should be triggered
+				presenter.EXPECT().BuildPlanned(requests)
+
+				Convey("When BuildFor is called", func() {
+					err := interactor.BuildPaths(ctx, paths, workflowID)
+
+					Convey("All expectaton should pass", func() {
+						So(err, ShouldBeNil)
+						ctrl.Finish()
+					})
+				})
+			})
+
 			Convey("Setup wait for second build until timeout", func() {
-				buildMaxSeconds = 5*time.Second + 500*time.Millisecond
-				buildCheckAfterSeconds = 1 * time.Second
+				interactor.timeout = 5*time.Second + 500*time.Millisecond
+				interactor.pollInterval = 1 * time.Second
 
 				for i, name := range projectNames {
 					buildID := buildIDs[i]
@@ -175,7 +1012,7 @@ func TestBuildProjectsInteractor(t *testing.T) {
 							gomock.Eq(name),
 						).
 						Return(utils.StrAddr(buildID), nil)
-					presenter.EXPECT().BuildTriggeredFor(name, buildID).Return()
+					presenter.EXPECT().BuildTriggeredFor(name, buildID, "").Return()
 
 					if i == 1 {
 						// no build status
@@ -184,7 +1021,7 @@ func TestBuildProjectsInteractor(t *testing.T) {
 								gomock.AssignableToTypeOf(ctxType),
 								gomock.Eq(buildID),
 							).
-							Return(nil, nil).
+							Return(statusOf(core.BuildStateRunning), nil).
 							MinTimes(6).MaxTimes(6)
 					} else {
 						// success build status
@@ -193,7 +1030,8 @@ func TestBuildProjectsInteractor(t *testing.T) {
 								gomock.AssignableToTypeOf(ctxType),
 								gomock.Eq(buildID),
 							).
-							Return(utils.StrAddr("success"), nil)
+							Return(statusOf(core.BuildStateSuccess), nil)
+						presenter.EXPECT().BuildSucceededFor(name, buildID)
 					}
 				}
 				// waiting for both project
@@ -201,7 +1039,7 @@ func TestBuildProjectsInteractor(t *testing.T) {
 				presenter.EXPECT().WaitingFor(infos).Return().
 					MinTimes(6).MaxTimes(6)
 				// should timeout
-				presenter.EXPECT().Timeout(buildMaxSeconds).Return()
+				presenter.EXPECT().Timeout(interactor.timeout).Return()
 
 				// it should kill all running builds
 				presenter.EXPECT().KillingBuilds(infos).Return()
@@ -213,11 +1051,18 @@ func TestBuildProjectsInteractor(t *testing.T) {
 					Return(nil)
 				// all running builds should have been killed
 				presenter.EXPECT().NotFinishedBuildsKilled().Return()
+				presenter.EXPECT().BuildTimedOutFor(projectNames[1], buildIDs[1])
+
+				presenter.EXPECT().BuildSummary([]*BuildResult{
+					{ProjectName: projectNames[0], BuildID: buildIDs[0], Outcome: BuildSucceeded},
+					{ProjectName: projectNames[1], BuildID: buildIDs[1], Outcome: BuildTimedOut},
+				})
 
 				Convey("When BuildFor is called", func() {
-					interactor.BuildPaths(ctx, paths, workflowID)
+					err := interactor.BuildPaths(ctx, paths, workflowID)
 
 					Convey("All expectaton should pass", func() {
+						So(err, ShouldEqual, ErrBuildTimeout)
 						ctrl.Finish()
 					})
 				})
@@ -225,3 +1070,74 @@ func TestBuildProjectsInteractor(t *testing.T) {
 		})
 	})
 }
+
+func statusOf(state core.BuildState) *core.BuildStatus {
+	return &core.BuildStatus{State: state}
+}
+
+// pipelineWithLogs is a pipeline gateway able to read build logs
+type pipelineWithLogs struct {
+	*mock_core.MockPipelineGateway
+	*mock_core.MockBuildLogGateway
+}
+
+// pipelineWithStatuses is a pipeline gateway able to report statuses of projects
+type pipelineWithStatuses struct {
+	*mock_core.MockPipelineGateway
+	*mock_core.MockCommitStatusGateway
+}
+
+func TestProjectNamesOfBuild(t *testing.T) {
+	cases := []*struct {
+		buildName string
+		want      []string
+	}{
+		{buildName: "app1", want: []string{"app1"}},
+		{buildName: "|app1|app2|", want: []string{"app1", "app2"}},
+		{buildName: "||", want: []string{}},
+	}
+
+	for i, v := range cases {
+		var (
+			buildName = v.buildName
+			want      = v.want
+		)
+		t.Run(fmt.Sprintf("Case %d, calls projectNamesOfBuild", i+1), func(t *testing.T) {
+			assert.Equal(t, want, projectNamesOfBuild(buildName))
+		})
+	}
+}
+
+func TestBuildProjectsInteractor_TimeoutFor(t *testing.T) {
+	interactor := &buildProjectsInteractor{
+		timeout:      10 * time.Minute,
+		pollInterval: 15 * time.Second,
+		projects: map[string]ProjectConfig{
+			"lint":   {Timeout: 2 * time.Minute, PollInterval: 5 * time.Second},
+			"mobile": {Timeout: 45 * time.Minute},
+		},
+	}
+	cases := []*struct {
+		buildName    string
+		timeout      time.Duration
+		pollInterval time.Duration
+	}{
+		{buildName: "app1", timeout: 10 * time.Minute, pollInterval: 15 * time.Second},
+		{buildName: "lint", timeout: 2 * time.Minute, pollInterval: 5 * time.Second},
+		{buildName: "|lint|app1|", timeout: 10 * time.Minute, pollInterval: 5 * time.Second},
+		{buildName: "|lint|mobile|", timeout: 45 * time.Minute, pollInterval: 5 * time.Second},
+		{buildName: "||", timeout: 10 * time.Minute, pollInterval: 15 * time.Second},
+	}
+
+	for i, v := range cases {
+		var (
+			buildName    = v.buildName
+			timeout      = v.timeout
+			pollInterval = v.pollInterval
+		)
+		t.Run(fmt.Sprintf("Case %d, calls timeoutFor and pollIntervalFor", i+1), func(t *testing.T) {
+			assert.Equal(t, timeout, interactor.timeoutFor(buildName))
+			assert.Equal(t, pollInterval, interactor.pollIntervalFor(buildName))
+		})
+	}
+}
@@ -12,27 +12,61 @@ import (
 	. "github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 )
 
-func NewListChangesInteractor(git core.GitGateway, pipeline core.PipelineGateway) ListChangesInteractor {
-	return &listChangesInteractor{git: git, pipeline: pipeline}
+func NewListChangesInteractor(
+	git core.GitGateway,
+	pipeline core.PipelineGateway,
+	presenter ListChangesOutput,
+	config ListChangesConfig,
+) ListChangesInteractor {
+	return &listChangesInteractor{
+		git:            git,
+		pipeline:       pipeline,
+		presenter:      presenter,
+		globalTriggers: globalTriggersOf(config.GlobalTriggers),
+	}
+}
+
+type ListChangesConfig struct {
+	// glob patterns of files, e.g., "go.mod" or ".github/workflows/**",
+	// that mark every path as changed when touched
+	GlobalTriggers []string
 }
 
 type listChangesInteractor struct {
-	git      core.GitGateway
-	pipeline core.PipelineGateway
+	git            core.GitGateway
+	pipeline       core.PipelineGateway
+	presenter      ListChangesOutput
+	globalTriggers []*globalTrigger
 }
 
 func (it *listChangesInteractor) ListChanges(ctx context.Context, paths []string, workflowID string) ([]string, error) {
+	listing, err := it.listChanges(ctx, paths, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	changedPaths := make([]string, len(listing.Projects))
+	for i, p := range listing.Projects {
+		changedPaths[i] = p.Path
+	}
+	return changedPaths, nil
+}
+
+// listChanges lists given paths with changes since the last successful build, named as projects
+func (it *listChangesInteractor) listChanges(ctx context.Context, paths []string, workflowID string) (*ProjectListing, error) {
 	lastCommit, err := it.pipeline.LastSuccessfulCommit(ctx, workflowID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "can't get last succesful commit for workflow ID %s", workflowID)
+		return nil, errors.Wrapf(&APIError{Err: err}, "can't get last succesful commit for workflow ID %s", workflowID)
 	}
 	currentCommit := it.pipeline.CurrentCommit()
+	it.presenter.ChangesBetween(lastCommit, currentCommit)
+	listing := &ProjectListing{BaseCommit: lastCommit, HeadCommit: currentCommit}
 	if lastCommit == "" {
 		files, err := it.git.FilesNameOnly(currentCommit)
 		if err != nil {
 			return nil, errors.Wrapf(err, `can't list file paths for commit "%s"`, currentCommit)
 		}
-		return filterOnlyPathsWithChanges(paths, files), nil
+		listing.Projects = changedProjectsFor(paths, files)
+		return listing, nil
 	}
 	// Since a local git repository might be a shallow clone,
 	// we have to ensure there is enough information for listing changes.
@@ -43,16 +77,32 @@ func (it *listChangesInteractor) ListChanges(ctx context.Context, paths []string
 		return nil, errors.Wrapf(err, `can't list changes from commit "%s" to "%s"`, lastCommit, currentCommit)
 	}
 
-	return filterOnlyPathsWithChanges(paths, changes), nil
+	changedPath, trigger := matchGlobalTrigger(it.globalTriggers, changes)
+	if trigger != "" {
+		it.presenter.AllPathsTriggeredBy(changedPath, trigger)
+		listing.GlobalTrigger = trigger
+		listing.Projects = make([]*ChangedProject, len(paths))
+		for i, path := range paths {
+			listing.Projects[i] = &ChangedProject{
+				Name:  projectNameFor(path),
+				Path:  path,
+				Files: []string{changedPath},
+			}
+		}
+		return listing, nil
+	}
+
+	listing.Projects = changedProjectsFor(paths, changes)
+	return listing, nil
 }
 
 func (it *listChangesInteractor) ListProjects(ctx context.Context, paths []string, workflowID string) ([]string, error) {
-	changedPaths, err := it.ListChanges(ctx, paths, workflowID)
+	listing, err := it.listChanges(ctx, paths, workflowID)
 	if err != nil {
 		return nil, errors.Wrapf(err, `can't list paths with changes for workflow ID "%s"`, workflowID)
 	}
-	projectNames := projectsNameFor(changedPaths)
-	return projectNames, nil
+	it.presenter.ProjectsListed(listing)
+	return projectNamesOf(listing), nil
 }
 
 const (
@@ -62,40 +112,165 @@ const (
 )
 
 func (it *listChangesInteractor) ListProjectsJoined(ctx context.Context, paths []string, workflowID string) (string, error) {
-	changedPaths, err := it.ListChanges(ctx, paths, workflowID)
+	listing, err := it.listChanges(ctx, paths, workflowID)
 	if err != nil {
 		return "", errors.Wrapf(err, `can't list paths with changes for workflow ID "%s"`, workflowID)
 	}
-	projectNames := projectsNameFor(changedPaths)
-	projectNamesJoined := fmt.Sprintf(
+	listing.Joined = fmt.Sprintf(
 		"%s%s%s",
 		joinProjectPrefix,
-		strings.Join(projectNames, joinProjectSeparater),
+		strings.Join(projectNamesOf(listing), joinProjectSeparater),
 		joinProjectPostfix,
 	)
-	return projectNamesJoined, nil
+	it.presenter.ProjectsListed(listing)
+	return listing.Joined, nil
+}
+
+// CommentProjects lists projects like ListProjects does, and reports why each is affected on the pull
+// request being built, along with links to their latest builds. The comment posted by a previous run
+// is updated in place, so running it again after builds fills in links to them.
+func (it *listChangesInteractor) CommentProjects(ctx context.Context, paths []string, workflowID string) ([]string, error) {
+	pr, ok := it.pipeline.(core.PullRequestGateway)
+	if ok != true {
+		return nil, &ConfigError{Err: errors.New("CI tool does not support commenting on pull requests")}
+	}
+	listing, err := it.listChanges(ctx, paths, workflowID)
+	if err != nil {
+		return nil, errors.Wrapf(err, `can't list paths with changes for workflow ID "%s"`, workflowID)
+	}
+	it.presenter.ProjectsListed(listing)
+	projectNames := projectNamesOf(listing)
+
+	number := pr.PullRequestNumber()
+	if number == 0 {
+		it.presenter.PullRequestCommented(0, "")
+		return projectNames, nil
+	}
+	buildURLs, err := pr.LatestBuildURLs(ctx, projectNames)
+	if err != nil {
+		return nil, errors.Wrap(&APIError{Err: err}, "can't get links to builds of projects")
+	}
+	report := affectedProjectsReportOf(listing, buildURLs)
+	commentURL, err := pr.CommentAffectedProjects(ctx, number, report)
+	if err != nil {
+		return nil, errors.Wrapf(&APIError{Err: err}, "can't comment on pull request #%d", number)
+	}
+	it.presenter.PullRequestCommented(number, commentURL)
+	return projectNames, nil
 }
 
-func filterOnlyPathsWithChanges(paths []string, changes []string) []string {
-	changesJoined := strings.Join(changes, "\n")
-	pathsWithChanges := make([]string, 0)
+func affectedProjectsReportOf(listing *ProjectListing, buildURLs map[string]string) *core.AffectedProjectsReport {
+	report := &core.AffectedProjectsReport{
+		BaseCommit: listing.BaseCommit,
+		HeadCommit: listing.HeadCommit,
+		Projects:   make([]*core.AffectedProject, len(listing.Projects)),
+	}
+	for i, p := range listing.Projects {
+		project := &core.AffectedProject{
+			Name:     p.Name,
+			Path:     p.Path,
+			Reason:   core.AffectedByChange,
+			Files:    p.Files,
+			BuildURL: buildURLs[p.Name],
+		}
+		if listing.GlobalTrigger != "" {
+			project.Reason = core.AffectedByDependency
+			project.Trigger = listing.GlobalTrigger
+		}
+		report.Projects[i] = project
+	}
+	return report
+}
+
+func changedProjectsFor(paths []string, changes []string) []*ChangedProject {
+	projects := make([]*ChangedProject, 0)
 	for _, path := range paths {
-		re := regexp.MustCompile(fmt.Sprintf(`(?m)^%s`, path))
-		if re.MatchString(changesJoined) == true {
-			pathsWithChanges = append(pathsWithChanges, path)
+		files := changedFilesUnder(path, changes)
+		if len(files) > 0 {
+			projects = append(projects, &ChangedProject{
+				Name:  projectNameFor(path),
+				Path:  path,
+				Files: files,
+			})
 		}
 	}
-	return pathsWithChanges
+	return projects
 }
 
-func projectsNameFor(paths []string) []string {
-	if len(paths) == 0 {
-		return []string{}
+func changedFilesUnder(path string, changes []string) []string {
+	re := regexp.MustCompile(fmt.Sprintf(`^%s`, path))
+	files := make([]string, 0)
+	for _, change := range changes {
+		if re.MatchString(change) == true {
+			files = append(files, change)
+		}
 	}
-	projectNames := make([]string, len(paths))
-	for i, path := range paths {
-		projectName := filepath.Base(path)
-		projectNames[i] = projectName
+	return files
+}
+
+func projectNamesOf(listing *ProjectListing) []string {
+	projectNames := make([]string, len(listing.Projects))
+	for i, p := range listing.Projects {
+		projectNames[i] = p.Name
 	}
 	return projectNames
 }
+
+// matchGlobalTrigger returns the first changed path matching any of global triggers,
+// and the matched trigger. Both are empty if none matches.
+func matchGlobalTrigger(triggers []*globalTrigger, changes []string) (string, string) {
+	for _, trigger := range triggers {
+		for _, change := range changes {
+			if trigger.re.MatchString(change) == true {
+				return change, trigger.pattern
+			}
+		}
+	}
+	return "", ""
+}
+
+// globalTrigger is a glob pattern of a global trigger, compiled once per run
+type globalTrigger struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func globalTriggersOf(patterns []string) []*globalTrigger {
+	triggers := make([]*globalTrigger, len(patterns))
+	for i, pattern := range patterns {
+		triggers[i] = &globalTrigger{pattern: pattern, re: globToRegexp(pattern)}
+	}
+	return triggers
+}
+
+// globToRegexp compiles a glob pattern matching a whole path, where "**" matches
+// any number of directories, including none for "**/", and "*" and "?" do not match
+// a path separator.
+func globToRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**/") {
+				b.WriteString("(.*/)?")
+				i += 2
+			} else if i+1 < len(glob) && glob[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func projectNameFor(path string) string {
+	return filepath.Base(path)
+}
@@ -7,12 +7,14 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/whatthefar/monorepo-toolkit/pkg/core"
 	mock_core "github.com/whatthefar/monorepo-toolkit/pkg/core/mock"
 	. "github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+	mock_interactor "github.com/whatthefar/monorepo-toolkit/pkg/interactor/mock"
 )
 
 const (
@@ -25,7 +27,9 @@ func TestNewListChnagesInteractor(t *testing.T) {
 
 	git := mock_core.NewMockGitGateway(ctrl)
 	pipeline := mock_core.NewMockPipelineGateway(ctrl)
-	interactor := NewListChangesInteractor(git, pipeline)
+	presenter := mock_interactor.NewMockListChangesOutput(ctrl)
+	config := ListChangesConfig{GlobalTriggers: []string{"go.mod"}}
+	interactor := NewListChangesInteractor(git, pipeline, presenter, config)
 
 	assert.Implements(t, (*ListChangesInteractor)(nil), interactor)
 	assert.IsType(t, new(listChangesInteractor), interactor)
@@ -37,6 +41,10 @@ func TestNewListChnagesInteractor(t *testing.T) {
 	assert.Equal(t, git, impl.git)
 	assert.NotNil(t, impl.pipeline)
 	assert.Equal(t, pipeline, impl.pipeline)
+	assert.NotNil(t, impl.presenter)
+	assert.Equal(t, presenter, impl.presenter)
+	assert.Len(t, impl.globalTriggers, 1)
+	assert.Equal(t, "go.mod", impl.globalTriggers[0].pattern)
 }
 
 func TestListChangesInteractor(t *testing.T) {
@@ -47,8 +55,14 @@ func TestListChangesInteractor(t *testing.T) {
 
 		git := mock_core.NewMockGitGateway(ctrl)
 		pipeline := mock_core.NewMockPipelineGateway(ctrl)
+		presenter := mock_interactor.NewMockListChangesOutput(ctrl)
 
-		interactor := &listChangesInteractor{git, pipeline}
+		interactor := &listChangesInteractor{
+			git:            git,
+			pipeline:       pipeline,
+			presenter:      presenter,
+			globalTriggers: globalTriggersOf([]string{"go.mod", ".github/workflows/**"}),
+		}
 
 		workflowID := "main.yml"
 		var lastCommit core.Hash
@@ -75,6 +89,7 @@ func TestListChangesInteractor(t *testing.T) {
 					pipeline.EXPECT().
 						CurrentCommit().
 						Return(currentCommit)
+					presenter.EXPECT().ChangesBetween(lastCommit, currentCommit)
 
 					git.EXPECT().
 						EnsureHavingCommitFromTip(
@@ -107,6 +122,7 @@ func TestListChangesInteractor(t *testing.T) {
 					pipeline.EXPECT().
 						CurrentCommit().
 						Return(currentCommit)
+					presenter.EXPECT().ChangesBetween(lastCommit, currentCommit)
 
 					git.EXPECT().
 						FilesNameOnly(
@@ -119,6 +135,40 @@ func TestListChangesInteractor(t *testing.T) {
 						}, nil)
 				},
 			},
+			{
+				paths:         []string{"services/app1", "services/app2"},
+				lastCommit:    "123",
+				currentCommit: "456",
+				// a global trigger is touched, all paths are considered changed
+				want: []string{"services/app1", "services/app2"},
+				expect: func() {
+					pipeline.EXPECT().
+						LastSuccessfulCommit(gomock.AssignableToTypeOf(ctxType), gomock.Eq(workflowID)).
+						Return(lastCommit, nil)
+					pipeline.EXPECT().
+						CurrentCommit().
+						Return(currentCommit)
+					presenter.EXPECT().ChangesBetween(lastCommit, currentCommit)
+
+					git.EXPECT().
+						EnsureHavingCommitFromTip(
+							gomock.AssignableToTypeOf(ctxType),
+							gomock.Eq(lastCommit),
+						).
+						Return(nil)
+					git.EXPECT().
+						DiffNameOnly(
+							gomock.Eq(lastCommit),
+							gomock.Eq(currentCommit),
+						).
+						Return([]string{
+							".github/workflows/main.yml",
+							"services/app1/README.md",
+						}, nil)
+					presenter.EXPECT().
+						AllPathsTriggeredBy(".github/workflows/main.yml", ".github/workflows/**")
+				},
+			},
 		}
 
 		for i, v := range cases {
@@ -144,11 +194,246 @@ func TestListChangesInteractor(t *testing.T) {
 	})
 }
 
-func TestFilterOnlyPathsWithChanges(t *testing.T) {
+func TestListChangesInteractor_ListProjects(t *testing.T) {
+	Convey("Given a listChangesInteractor", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		git := mock_core.NewMockGitGateway(ctrl)
+		pipeline := mock_core.NewMockPipelineGateway(ctrl)
+		presenter := mock_interactor.NewMockListChangesOutput(ctrl)
+
+		interactor := &listChangesInteractor{
+			git:       git,
+			pipeline:  pipeline,
+			presenter: presenter,
+		}
+
+		paths := []string{"services/app1", "services/app2", "services/app3"}
+		workflowID := "main.yml"
+		lastCommit := core.Hash("123")
+		currentCommit := core.Hash("456")
+		ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+		pipeline.EXPECT().
+			LastSuccessfulCommit(gomock.AssignableToTypeOf(ctxType), gomock.Eq(workflowID)).
+			Return(lastCommit, nil)
+		pipeline.EXPECT().CurrentCommit().Return(currentCommit)
+		presenter.EXPECT().ChangesBetween(lastCommit, currentCommit)
+		git.EXPECT().
+			EnsureHavingCommitFromTip(gomock.AssignableToTypeOf(ctxType), gomock.Eq(lastCommit)).
+			Return(nil)
+		git.EXPECT().
+			DiffNameOnly(gomock.Eq(lastCommit), gomock.Eq(currentCommit)).
+			Return([]string{
+				"services/app1/main.go",
+				"services/app1/README.md",
+				"services/app3/README.md",
+			}, nil)
+
+		listing := &ProjectListing{
+			BaseCommit: lastCommit,
+			HeadCommit: currentCommit,
+			Projects: []*ChangedProject{
+				{Name: "app1", Path: "services/app1", Files: []string{"services/app1/main.go", "services/app1/README.md"}},
+				{Name: "app3", Path: "services/app3", Files: []string{"services/app3/README.md"}},
+			},
+		}
+
+		Convey("When calls ListProjects", func() {
+			presenter.EXPECT().ProjectsListed(listing)
+			got, err := interactor.ListProjects(ctx, paths, workflowID)
+
+			Convey("It should list project names, and present the listing", func() {
+				So(err, ShouldBeNil)
+				So(got, ShouldResemble, []string{"app1", "app3"})
+				ctrl.Finish()
+			})
+		})
+
+		Convey("When calls ListProjectsJoined", func() {
+			listing.Joined = "|app1|app3|"
+			presenter.EXPECT().ProjectsListed(listing)
+			got, err := interactor.ListProjectsJoined(ctx, paths, workflowID)
+
+			Convey("It should join project names, and present the listing", func() {
+				So(err, ShouldBeNil)
+				So(got, ShouldEqual, "|app1|app3|")
+				ctrl.Finish()
+			})
+		})
+	})
+}
+
+func TestListChangesInteractor_CommentProjects(t *testing.T) {
+	Convey("Given a listChangesInteractor with a pipeline able to comment on pull requests", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+
+		git := mock_core.NewMockGitGateway(ctrl)
+		pipeline := mock_core.NewMockPipelineGateway(ctrl)
+		pullRequests := mock_core.NewMockPullRequestGateway(ctrl)
+		presenter := mock_interactor.NewMockListChangesOutput(ctrl)
+
+		interactor := &listChangesInteractor{
+			git:            git,
+			pipeline:       &pipelineWithPullRequests{pipeline, pullRequests},
+			presenter:      presenter,
+			globalTriggers: globalTriggersOf([]string{"go.mod"}),
+		}
+
+		paths := []string{"services/app1", "services/app2"}
+		workflowID := "main.yml"
+		lastCommit := core.Hash("123")
+		currentCommit := core.Hash("456")
+		ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+		pipeline.EXPECT().
+			LastSuccessfulCommit(gomock.AssignableToTypeOf(ctxType), gomock.Eq(workflowID)).
+			Return(lastCommit, nil)
+		pipeline.EXPECT().CurrentCommit().Return(currentCommit)
+		presenter.EXPECT().ChangesBetween(lastCommit, currentCommit)
+		git.EXPECT().
+			EnsureHavingCommitFromTip(gomock.AssignableToTypeOf(ctxType), gomock.Eq(lastCommit)).
+			Return(nil)
+
+		Convey("Given files of a project are changed", func() {
+			git.EXPECT().
+				DiffNameOnly(gomock.Eq(lastCommit), gomock.Eq(currentCommit)).
+				Return([]string{"services/app1/main.go"}, nil)
+			presenter.EXPECT().ProjectsListed(gomock.Any())
+
+			Convey("When calls CommentProjects for a pull request", func() {
+				pullRequests.EXPECT().PullRequestNumber().Return(12)
+				pullRequests.EXPECT().
+					LatestBuildURLs(gomock.AssignableToTypeOf(ctxType), []string{"app1"}).
+					Return(map[string]string{"app1": "https://github.com/owner/repo/actions/runs/1"}, nil)
+				pullRequests.EXPECT().
+					CommentAffectedProjects(gomock.AssignableToTypeOf(ctxType), 12, &core.AffectedProjectsReport{
+						BaseCommit: lastCommit,
+						HeadCommit: currentCommit,
+						Projects: []*core.AffectedProject{
+							{
+								Name:     "app1",
+								Path:     "services/app1",
+								Reason:   core.AffectedByChange,
+								Files:    []string{"services/app1/main.go"},
+								BuildURL: "https://github.com/owner/repo/actions/runs/1",
+							},
+						},
+					}).
+					Return("https://github.com/owner/repo/pull/12#issuecomment-1", nil)
+				presenter.EXPECT().PullRequestCommented(12, "https://github.com/owner/repo/pull/12#issuecomment-1")
+
+				got, err := interactor.CommentProjects(ctx, paths, workflowID)
+
+				Convey("It should comment directly changed projects, with links to their builds", func() {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, []string{"app1"})
+					ctrl.Finish()
+				})
+			})
+
+			Convey("When calls CommentProjects not for a pull request", func() {
+				pullRequests.EXPECT().PullRequestNumber().Return(0)
+				presenter.EXPECT().PullRequestCommented(0, "")
+
+				got, err := interactor.CommentProjects(ctx, paths, workflowID)
+
+				Convey("It should list projects without commenting", func() {
+					So(err, ShouldBeNil)
+					So(got, ShouldResemble, []string{"app1"})
+					ctrl.Finish()
+				})
+			})
+
+			Convey("When calls CommentProjects, and commenting fails", func() {
+				pullRequests.EXPECT().PullRequestNumber().Return(12)
+				pullRequests.EXPECT().
+					LatestBuildURLs(gomock.AssignableToTypeOf(ctxType), []string{"app1"}).
+					Return(map[string]string{}, nil)
+				pullRequests.EXPECT().
+					CommentAffectedProjects(gomock.AssignableToTypeOf(ctxType), 12, gomock.Any()).
+					Return("", errors.New("resource not accessible by integration"))
+
+				_, err := interactor.CommentProjects(ctx, paths, workflowID)
+
+				Convey("It should return the error", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldContainSubstring, "resource not accessible by integration")
+					ctrl.Finish()
+				})
+			})
+		})
+
+		Convey("Given a global trigger is changed", func() {
+			git.EXPECT().
+				DiffNameOnly(gomock.Eq(lastCommit), gomock.Eq(currentCommit)).
+				Return([]string{"go.mod", "services/app1/main.go"}, nil)
+			presenter.EXPECT().AllPathsTriggeredBy("go.mod", "go.mod")
+			presenter.EXPECT().ProjectsListed(gomock.Any())
+
+			Convey("When calls CommentProjects for a pull request", func() {
+				var report *core.AffectedProjectsReport
+				pullRequests.EXPECT().PullRequestNumber().Return(12)
+				pullRequests.EXPECT().
+					LatestBuildURLs(gomock.AssignableToTypeOf(ctxType), []string{"app1", "app2"}).
+					Return(map[string]string{}, nil)
+				pullRequests.EXPECT().
+					CommentAffectedProjects(gomock.AssignableToTypeOf(ctxType), 12, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, r *core.AffectedProjectsReport) (string, error) {
+						report = r
+						return "https://github.com/owner/repo/pull/12#issuecomment-1", nil
+					})
+				presenter.EXPECT().PullRequestCommented(12, gomock.Any())
+
+				_, err := interactor.CommentProjects(ctx, paths, workflowID)
+
+				Convey("It should comment every project as affected by the dependency", func() {
+					So(err, ShouldBeNil)
+					So(report.Projects, ShouldHaveLength, 2)
+					for _, p := range report.Projects {
+						So(p.Reason, ShouldEqual, core.AffectedByDependency)
+						So(p.Trigger, ShouldEqual, "go.mod")
+						So(p.Files, ShouldResemble, []string{"go.mod"})
+					}
+					ctrl.Finish()
+				})
+			})
+		})
+	})
+
+	Convey("Given a listChangesInteractor with a pipeline unable to comment on pull requests", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		interactor := &listChangesInteractor{
+			git:       mock_core.NewMockGitGateway(ctrl),
+			pipeline:  mock_core.NewMockPipelineGateway(ctrl),
+			presenter: mock_interactor.NewMockListChangesOutput(ctrl),
+		}
+
+		Convey("When calls CommentProjects", func() {
+			_, err := interactor.CommentProjects(context.Background(), []string{"services/app1"}, "main.yml")
+
+			Convey("It should fail before listing changes", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// pipelineWithPullRequests is a pipeline gateway able to comment on pull requests
+type pipelineWithPullRequests struct {
+	*mock_core.MockPipelineGateway
+	*mock_core.MockPullRequestGateway
+}
+
+func TestChangedProjectsFor(t *testing.T) {
 	cases := []*struct {
 		paths    []string
 		changes  []string
-		expected []string
+		expected []*ChangedProject
 	}{
 		{
 			paths: []string{"services/app1", "services/app2"},
@@ -157,7 +442,10 @@ func TestFilterOnlyPathsWithChanges(t *testing.T) {
 				"services/app2/README.md",
 				"services/app3/README.md",
 			},
-			expected: []string{"services/app1", "services/app2"},
+			expected: []*ChangedProject{
+				{Name: "app1", Path: "services/app1", Files: []string{"services/app1/README.md"}},
+				{Name: "app2", Path: "services/app2", Files: []string{"services/app2/README.md"}},
+			},
 		},
 		{
 			paths: []string{"services/app1/README.md", "services/app2/README.md"},
@@ -166,16 +454,21 @@ func TestFilterOnlyPathsWithChanges(t *testing.T) {
 				"services/app2/README.md",
 				"services/app3/README.md",
 			},
-			expected: []string{"services/app1/README.md", "services/app2/README.md"},
+			expected: []*ChangedProject{
+				{Name: "README.md", Path: "services/app1/README.md", Files: []string{"services/app1/README.md"}},
+				{Name: "README.md", Path: "services/app2/README.md", Files: []string{"services/app2/README.md"}},
+			},
 		},
 		{
 			paths: []string{"pkg", "services/app3"},
 			changes: []string{
 				"services/app1/README.md",
-				"services/app2/README.md",
+				"services/app3/main.go",
 				"services/app3/README.md",
 			},
-			expected: []string{"services/app3"},
+			expected: []*ChangedProject{
+				{Name: "app3", Path: "services/app3", Files: []string{"services/app3/main.go", "services/app3/README.md"}},
+			},
 		},
 	}
 
@@ -185,11 +478,86 @@ func TestFilterOnlyPathsWithChanges(t *testing.T) {
 			changes = v.changes
 			want    = v.expected
 		)
-		t.Run(fmt.Sprintf("Case %d, filterOnlyPathsWithChanges should work", i), func(t *testing.T) {
-			var got []string
-			got = filterOnlyPathsWithChanges(paths, changes)
+		t.Run(fmt.Sprintf("Case %d, changedProjectsFor should work", i), func(t *testing.T) {
+			got := changedProjectsFor(paths, changes)
 
 			assert.Equal(t, want, got)
 		})
 	}
 }
+
+func TestMatchGlobalTrigger(t *testing.T) {
+	cases := []*struct {
+		triggers    []string
+		changes     []string
+		changedPath string
+		trigger     string
+	}{
+		{
+			triggers:    []string{"go.mod"},
+			changes:     []string{"services/app1/go.mod", "go.mod"},
+			changedPath: "go.mod",
+			trigger:     "go.mod",
+		},
+		{
+			// "*" should not match a path separator
+			triggers:    []string{"Dockerfile.*", "*.yml"},
+			changes:     []string{"services/app1/Dockerfile.base", ".github/workflows/main.yml"},
+			changedPath: "",
+			trigger:     "",
+		},
+		{
+			triggers:    []string{"go.mod", ".github/**"},
+			changes:     []string{"services/app1/main.go", ".github/workflows/main.yml"},
+			changedPath: ".github/workflows/main.yml",
+			trigger:     ".github/**",
+		},
+		{
+			// "**/" matches no directory too
+			triggers:    []string{"**/go.mod"},
+			changes:     []string{"services/app1/main.go", "go.mod"},
+			changedPath: "go.mod",
+			trigger:     "**/go.mod",
+		},
+		{
+			triggers:    []string{"**/go.mod"},
+			changes:     []string{"services/app1/go.mod"},
+			changedPath: "services/app1/go.mod",
+			trigger:     "**/go.mod",
+		},
+		{
+			triggers:    []string{"services/**/go.mod"},
+			changes:     []string{"services/go.mod"},
+			changedPath: "services/go.mod",
+			trigger:     "services/**/go.mod",
+		},
+		{
+			triggers:    []string{"**/go.mod"},
+			changes:     []string{"go.sum", "services/app1/go.mod.bak"},
+			changedPath: "",
+			trigger:     "",
+		},
+		{
+			// no global trigger
+			triggers:    nil,
+			changes:     []string{"go.mod"},
+			changedPath: "",
+			trigger:     "",
+		},
+	}
+
+	for i, v := range cases {
+		var (
+			triggers        = v.triggers
+			changes         = v.changes
+			wantChangedPath = v.changedPath
+			wantTrigger     = v.trigger
+		)
+		t.Run(fmt.Sprintf("Case %d, matchGlobalTrigger should work", i), func(t *testing.T) {
+			changedPath, trigger := matchGlobalTrigger(globalTriggersOf(triggers), changes)
+
+			assert.Equal(t, wantChangedPath, changedPath)
+			assert.Equal(t, wantTrigger, trigger)
+		})
+	}
+}
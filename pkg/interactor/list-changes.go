@@ -1,13 +1,47 @@
-//go:generate mockgen -destination mock/list-changes.go . ListChangesInteractor
+//go:generate mockgen -destination mock/list-changes.go . ListChangesInteractor,ListChangesOutput
 
 package interactor
 
 import (
 	"context"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
 )
 
 type ListChangesInteractor interface {
 	ListChanges(ctx context.Context, paths []string, workflowID string) (changedPaths []string, err error)
 	ListProjects(ctx context.Context, paths []string, workflowID string) (projectNames []string, err error)
 	ListProjectsJoined(ctx context.Context, paths []string, workflowID string) (projectName string, err error)
+	// list projects with changes, and report them on the pull request being built, if any
+	CommentProjects(ctx context.Context, paths []string, workflowID string) (projectNames []string, err error)
+}
+
+type ListChangesOutput interface {
+	// changes are listed between given commits, all files of the current commit if lastCommit is empty
+	ChangesBetween(lastCommit core.Hash, currentCommit core.Hash)
+	// all paths are considered changed, since a changed file matches a global trigger
+	AllPathsTriggeredBy(changedPath string, trigger string)
+	// projects with changes are listed, by ListProjects or ListProjectsJoined
+	ProjectsListed(listing *ProjectListing)
+	// affected projects are reported on given pull request, by CommentProjects
+	// number is zero if not built for a pull request, and nothing is reported
+	PullRequestCommented(number int, commentURL string)
+}
+
+type ProjectListing struct {
+	// empty if no successful build is found, all files of HeadCommit are considered changed
+	BaseCommit core.Hash
+	HeadCommit core.Hash
+	// the global trigger matched by a changed file, empty if none
+	GlobalTrigger string
+	Projects      []*ChangedProject
+	// projects joined into a single project, set only by ListProjectsJoined
+	Joined string
+}
+
+type ChangedProject struct {
+	Name string
+	Path string
+	// changed files under the path, or the changed file matching a global trigger
+	Files []string
 }
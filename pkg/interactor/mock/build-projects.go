@@ -6,6 +6,7 @@ package mock_interactor
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	core "github.com/whatthefar/monorepo-toolkit/pkg/core"
 	interactor "github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 	reflect "reflect"
 	time "time"
@@ -46,6 +47,30 @@ func (mr *MockBuildProjectsOutputMockRecorder) AllBuildSucceeded(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllBuildSucceeded", reflect.TypeOf((*MockBuildProjectsOutput)(nil).AllBuildSucceeded), arg0)
 }
 
+// AllPathsTriggeredBy mocks base method
+func (m *MockBuildProjectsOutput) AllPathsTriggeredBy(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AllPathsTriggeredBy", arg0, arg1)
+}
+
+// AllPathsTriggeredBy indicates an expected call of AllPathsTriggeredBy
+func (mr *MockBuildProjectsOutputMockRecorder) AllPathsTriggeredBy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllPathsTriggeredBy", reflect.TypeOf((*MockBuildProjectsOutput)(nil).AllPathsTriggeredBy), arg0, arg1)
+}
+
+// BuildCancelledFor mocks base method
+func (m *MockBuildProjectsOutput) BuildCancelledFor(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BuildCancelledFor", arg0, arg1)
+}
+
+// BuildCancelledFor indicates an expected call of BuildCancelledFor
+func (mr *MockBuildProjectsOutputMockRecorder) BuildCancelledFor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildCancelledFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildCancelledFor), arg0, arg1)
+}
+
 // BuildFailedFor mocks base method
 func (m *MockBuildProjectsOutput) BuildFailedFor(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -58,6 +83,42 @@ func (mr *MockBuildProjectsOutputMockRecorder) BuildFailedFor(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildFailedFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildFailedFor), arg0, arg1)
 }
 
+// BuildLogError mocks base method
+func (m *MockBuildProjectsOutput) BuildLogError(arg0 string, arg1 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BuildLogError", arg0, arg1)
+}
+
+// BuildLogError indicates an expected call of BuildLogError
+func (mr *MockBuildProjectsOutputMockRecorder) BuildLogError(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildLogError", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildLogError), arg0, arg1)
+}
+
+// BuildLogFor mocks base method
+func (m *MockBuildProjectsOutput) BuildLogFor(arg0, arg1 string, arg2 []*core.LogExcerpt) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BuildLogFor", arg0, arg1, arg2)
+}
+
+// BuildLogFor indicates an expected call of BuildLogFor
+func (mr *MockBuildProjectsOutputMockRecorder) BuildLogFor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildLogFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildLogFor), arg0, arg1, arg2)
+}
+
+// BuildPlanned mocks base method
+func (m *MockBuildProjectsOutput) BuildPlanned(arg0 []*core.BuildRequest) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BuildPlanned", arg0)
+}
+
+// BuildPlanned indicates an expected call of BuildPlanned
+func (mr *MockBuildProjectsOutputMockRecorder) BuildPlanned(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildPlanned", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildPlanned), arg0)
+}
+
 // BuildSkippedFor mocks base method
 func (m *MockBuildProjectsOutput) BuildSkippedFor(arg0 string) {
 	m.ctrl.T.Helper()
@@ -70,16 +131,88 @@ func (mr *MockBuildProjectsOutputMockRecorder) BuildSkippedFor(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildSkippedFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildSkippedFor), arg0)
 }
 
+// BuildSucceededFor mocks base method
+func (m *MockBuildProjectsOutput) BuildSucceededFor(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BuildSucceededFor", arg0, arg1)
+}
+
+// BuildSucceededFor indicates an expected call of BuildSucceededFor
+func (mr *MockBuildProjectsOutputMockRecorder) BuildSucceededFor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildSucceededFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildSucceededFor), arg0, arg1)
+}
+
+// BuildSummary mocks base method
+func (m *MockBuildProjectsOutput) BuildSummary(arg0 []*interactor.BuildResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BuildSummary", arg0)
+}
+
+// BuildSummary indicates an expected call of BuildSummary
+func (mr *MockBuildProjectsOutputMockRecorder) BuildSummary(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildSummary", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildSummary), arg0)
+}
+
+// BuildTimedOutFor mocks base method
+func (m *MockBuildProjectsOutput) BuildTimedOutFor(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BuildTimedOutFor", arg0, arg1)
+}
+
+// BuildTimedOutFor indicates an expected call of BuildTimedOutFor
+func (mr *MockBuildProjectsOutputMockRecorder) BuildTimedOutFor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildTimedOutFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildTimedOutFor), arg0, arg1)
+}
+
 // BuildTriggeredFor mocks base method
-func (m *MockBuildProjectsOutput) BuildTriggeredFor(arg0, arg1 string) {
+func (m *MockBuildProjectsOutput) BuildTriggeredFor(arg0, arg1, arg2 string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "BuildTriggeredFor", arg0, arg1)
+	m.ctrl.Call(m, "BuildTriggeredFor", arg0, arg1, arg2)
 }
 
 // BuildTriggeredFor indicates an expected call of BuildTriggeredFor
-func (mr *MockBuildProjectsOutputMockRecorder) BuildTriggeredFor(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockBuildProjectsOutputMockRecorder) BuildTriggeredFor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildTriggeredFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildTriggeredFor), arg0, arg1, arg2)
+}
+
+// Cancelled mocks base method
+func (m *MockBuildProjectsOutput) Cancelled() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Cancelled")
+}
+
+// Cancelled indicates an expected call of Cancelled
+func (mr *MockBuildProjectsOutputMockRecorder) Cancelled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancelled", reflect.TypeOf((*MockBuildProjectsOutput)(nil).Cancelled))
+}
+
+// ChangesBetween mocks base method
+func (m *MockBuildProjectsOutput) ChangesBetween(arg0, arg1 core.Hash) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ChangesBetween", arg0, arg1)
+}
+
+// ChangesBetween indicates an expected call of ChangesBetween
+func (mr *MockBuildProjectsOutputMockRecorder) ChangesBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangesBetween", reflect.TypeOf((*MockBuildProjectsOutput)(nil).ChangesBetween), arg0, arg1)
+}
+
+// CommitStatusError mocks base method
+func (m *MockBuildProjectsOutput) CommitStatusError(arg0 string, arg1 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CommitStatusError", arg0, arg1)
+}
+
+// CommitStatusError indicates an expected call of CommitStatusError
+func (mr *MockBuildProjectsOutputMockRecorder) CommitStatusError(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildTriggeredFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).BuildTriggeredFor), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitStatusError", reflect.TypeOf((*MockBuildProjectsOutput)(nil).CommitStatusError), arg0, arg1)
 }
 
 // KillBuildError mocks base method
@@ -130,16 +263,52 @@ func (mr *MockBuildProjectsOutputMockRecorder) NotFinishedBuildsKilled() *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotFinishedBuildsKilled", reflect.TypeOf((*MockBuildProjectsOutput)(nil).NotFinishedBuildsKilled))
 }
 
-// ThrowError mocks base method
-func (m *MockBuildProjectsOutput) ThrowError(arg0 error) {
+// ProjectsListed mocks base method
+func (m *MockBuildProjectsOutput) ProjectsListed(arg0 *interactor.ProjectListing) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ProjectsListed", arg0)
+}
+
+// ProjectsListed indicates an expected call of ProjectsListed
+func (mr *MockBuildProjectsOutputMockRecorder) ProjectsListed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectsListed", reflect.TypeOf((*MockBuildProjectsOutput)(nil).ProjectsListed), arg0)
+}
+
+// PullRequestCommented mocks base method
+func (m *MockBuildProjectsOutput) PullRequestCommented(arg0 int, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PullRequestCommented", arg0, arg1)
+}
+
+// PullRequestCommented indicates an expected call of PullRequestCommented
+func (mr *MockBuildProjectsOutputMockRecorder) PullRequestCommented(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestCommented", reflect.TypeOf((*MockBuildProjectsOutput)(nil).PullRequestCommented), arg0, arg1)
+}
+
+// RetryingFailedBuildFor mocks base method
+func (m *MockBuildProjectsOutput) RetryingFailedBuildFor(arg0, arg1 string, arg2, arg3 int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RetryingFailedBuildFor", arg0, arg1, arg2, arg3)
+}
+
+// RetryingFailedBuildFor indicates an expected call of RetryingFailedBuildFor
+func (mr *MockBuildProjectsOutputMockRecorder) RetryingFailedBuildFor(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryingFailedBuildFor", reflect.TypeOf((*MockBuildProjectsOutput)(nil).RetryingFailedBuildFor), arg0, arg1, arg2, arg3)
+}
+
+// RetryingRequest mocks base method
+func (m *MockBuildProjectsOutput) RetryingRequest(arg0 string, arg1 int, arg2 time.Duration, arg3 error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "ThrowError", arg0)
+	m.ctrl.Call(m, "RetryingRequest", arg0, arg1, arg2, arg3)
 }
 
-// ThrowError indicates an expected call of ThrowError
-func (mr *MockBuildProjectsOutputMockRecorder) ThrowError(arg0 interface{}) *gomock.Call {
+// RetryingRequest indicates an expected call of RetryingRequest
+func (mr *MockBuildProjectsOutputMockRecorder) RetryingRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ThrowError", reflect.TypeOf((*MockBuildProjectsOutput)(nil).ThrowError), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryingRequest", reflect.TypeOf((*MockBuildProjectsOutput)(nil).RetryingRequest), arg0, arg1, arg2, arg3)
 }
 
 // Timeout mocks base method
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/whatthefar/monorepo-toolkit/pkg/interactor (interfaces: ListChangesInteractor)
+// Source: github.com/whatthefar/monorepo-toolkit/pkg/interactor (interfaces: ListChangesInteractor,ListChangesOutput)
 
 // Package mock_interactor is a generated GoMock package.
 package mock_interactor
@@ -7,6 +7,8 @@ package mock_interactor
 import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
+	core "github.com/whatthefar/monorepo-toolkit/pkg/core"
+	interactor "github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 	reflect "reflect"
 )
 
@@ -33,6 +35,21 @@ func (m *MockListChangesInteractor) EXPECT() *MockListChangesInteractorMockRecor
 	return m.recorder
 }
 
+// CommentProjects mocks base method
+func (m *MockListChangesInteractor) CommentProjects(arg0 context.Context, arg1 []string, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommentProjects", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommentProjects indicates an expected call of CommentProjects
+func (mr *MockListChangesInteractorMockRecorder) CommentProjects(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommentProjects", reflect.TypeOf((*MockListChangesInteractor)(nil).CommentProjects), arg0, arg1, arg2)
+}
+
 // ListChanges mocks base method
 func (m *MockListChangesInteractor) ListChanges(arg0 context.Context, arg1 []string, arg2 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -77,3 +94,74 @@ func (mr *MockListChangesInteractorMockRecorder) ListProjectsJoined(arg0, arg1,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjectsJoined", reflect.TypeOf((*MockListChangesInteractor)(nil).ListProjectsJoined), arg0, arg1, arg2)
 }
+
+// MockListChangesOutput is a mock of ListChangesOutput interface
+type MockListChangesOutput struct {
+	ctrl     *gomock.Controller
+	recorder *MockListChangesOutputMockRecorder
+}
+
+// MockListChangesOutputMockRecorder is the mock recorder for MockListChangesOutput
+type MockListChangesOutputMockRecorder struct {
+	mock *MockListChangesOutput
+}
+
+// NewMockListChangesOutput creates a new mock instance
+func NewMockListChangesOutput(ctrl *gomock.Controller) *MockListChangesOutput {
+	mock := &MockListChangesOutput{ctrl: ctrl}
+	mock.recorder = &MockListChangesOutputMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockListChangesOutput) EXPECT() *MockListChangesOutputMockRecorder {
+	return m.recorder
+}
+
+// AllPathsTriggeredBy mocks base method
+func (m *MockListChangesOutput) AllPathsTriggeredBy(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AllPathsTriggeredBy", arg0, arg1)
+}
+
+// AllPathsTriggeredBy indicates an expected call of AllPathsTriggeredBy
+func (mr *MockListChangesOutputMockRecorder) AllPathsTriggeredBy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllPathsTriggeredBy", reflect.TypeOf((*MockListChangesOutput)(nil).AllPathsTriggeredBy), arg0, arg1)
+}
+
+// ChangesBetween mocks base method
+func (m *MockListChangesOutput) ChangesBetween(arg0, arg1 core.Hash) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ChangesBetween", arg0, arg1)
+}
+
+// ChangesBetween indicates an expected call of ChangesBetween
+func (mr *MockListChangesOutputMockRecorder) ChangesBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangesBetween", reflect.TypeOf((*MockListChangesOutput)(nil).ChangesBetween), arg0, arg1)
+}
+
+// ProjectsListed mocks base method
+func (m *MockListChangesOutput) ProjectsListed(arg0 *interactor.ProjectListing) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ProjectsListed", arg0)
+}
+
+// ProjectsListed indicates an expected call of ProjectsListed
+func (mr *MockListChangesOutputMockRecorder) ProjectsListed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectsListed", reflect.TypeOf((*MockListChangesOutput)(nil).ProjectsListed), arg0)
+}
+
+// PullRequestCommented mocks base method
+func (m *MockListChangesOutput) PullRequestCommented(arg0 int, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PullRequestCommented", arg0, arg1)
+}
+
+// PullRequestCommented indicates an expected call of PullRequestCommented
+func (mr *MockListChangesOutputMockRecorder) PullRequestCommented(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequestCommented", reflect.TypeOf((*MockListChangesOutput)(nil).PullRequestCommented), arg0, arg1)
+}
@@ -4,7 +4,6 @@ package controller
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
 
@@ -18,6 +17,7 @@ type CI interface {
 
 	ListProjects(ctx context.Context, paths []string, workflowID string) ([]string, error)
 	ListProjectsJoined(ctx context.Context, paths []string, workflowID string) (string, error)
+	CommentProjects(ctx context.Context, paths []string, workflowID string) ([]string, error)
 }
 
 func NewCIController(
@@ -42,9 +42,9 @@ func (c *ci) Build(ctx context.Context, paths []string, workflowID string) error
 	}
 
 	paths = relPathsIfPossible(workDir, paths)
-	c.BuildPaths(ctx, paths, workflowID)
-	return nil
+	return c.BuildPaths(ctx, paths, workflowID)
 }
+
 func (c *ci) BuildOnce(ctx context.Context, paths []string, workflowID string) error {
 	workDir, err := os.Getwd()
 	if err != nil {
@@ -52,8 +52,7 @@ func (c *ci) BuildOnce(ctx context.Context, paths []string, workflowID string) e
 	}
 
 	paths = relPathsIfPossible(workDir, paths)
-	c.BuildPaths(ctx, paths, workflowID)
-	return nil
+	return c.BuildPathsOnce(ctx, paths, workflowID)
 }
 
 func (c *ci) ListProjects(ctx context.Context, paths []string, workflowID string) ([]string, error) {
@@ -67,10 +66,6 @@ func (c *ci) ListProjects(ctx context.Context, paths []string, workflowID string
 	if err != nil {
 		return nil, errors.Wrap(err, "can't list projects that have changes")
 	}
-	// TODO: use presenter
-	for _, project := range projects {
-		fmt.Println(project)
-	}
 	return projects, nil
 }
 
@@ -85,11 +80,23 @@ func (c *ci) ListProjectsJoined(ctx context.Context, paths []string, workflowID
 	if err != nil {
 		return "", errors.Wrap(err, "can't list projects that have changes")
 	}
-	// TODO: use presenter
-	fmt.Println(project)
 	return project, nil
 }
 
+func (c *ci) CommentProjects(ctx context.Context, paths []string, workflowID string) ([]string, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get current directory")
+	}
+
+	paths = relPathsIfPossible(workDir, paths)
+	projects, err := c.ListChangesInteractor.CommentProjects(ctx, paths, workflowID)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't comment projects that have changes")
+	}
+	return projects, nil
+}
+
 func relPathsIfPossible(workDir string, paths []string) []string {
 	for i, path := range paths {
 		if filepath.IsAbs(path) {
@@ -61,6 +61,21 @@ func (mr *MockCIMockRecorder) BuildOnce(arg0, arg1, arg2 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildOnce", reflect.TypeOf((*MockCI)(nil).BuildOnce), arg0, arg1, arg2)
 }
 
+// CommentProjects mocks base method
+func (m *MockCI) CommentProjects(arg0 context.Context, arg1 []string, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommentProjects", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommentProjects indicates an expected call of CommentProjects
+func (mr *MockCIMockRecorder) CommentProjects(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommentProjects", reflect.TypeOf((*MockCI)(nil).CommentProjects), arg0, arg1, arg2)
+}
+
 // ListProjects mocks base method
 func (m *MockCI) ListProjects(arg0 context.Context, arg1 []string, arg2 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,241 @@
+package presenter
+
+import (
+	"io"
+	"time"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// NewEncodedBuildProjectsPresenter creates a presenter writing a stream of events to out,
+// one JSON object per line or one YAML document per event
+func NewEncodedBuildProjectsPresenter(out io.Writer, format Format) interactor.BuildProjectsOutput {
+	return &encodedBuildProjectsPresenter{out: out, marshal: marshalerOf(format), now: time.Now}
+}
+
+type encodedBuildProjectsPresenter struct {
+	out     io.Writer
+	marshal marshaler
+	now     func() time.Time
+}
+
+// Build event names, events of a single build start with "build-"
+const (
+	eventGlobalTrigger     = "global-trigger"
+	eventChanges           = "changes"
+	eventPlanned           = "planned"
+	eventBuildTriggered    = "build-triggered"
+	eventBuildNotTriggered = "build-not-triggered"
+	eventBuildSucceeded    = "build-succeeded"
+	eventBuildFailed       = "build-failed"
+	eventBuildLog          = "build-log"
+	eventBuildLogError     = "build-log-error"
+	eventBuildCancelled    = "build-cancelled"
+	eventBuildRetrying     = "build-retrying"
+	eventBuildSkipped      = "build-skipped"
+	eventBuildTimedOut     = "build-timed-out"
+	eventWaiting           = "waiting"
+	eventSucceeded         = "succeeded"
+	eventSummary           = "summary"
+	eventTimeout           = "timeout"
+	eventKilling           = "killing"
+	eventKillError         = "kill-error"
+	eventKilled            = "killed"
+	eventCommitStatusError = "commit-status-error"
+	eventInterrupted       = "interrupted"
+	eventRetryingRequest   = "retrying-request"
+)
+
+type buildEventDTO struct {
+	Event       string             `json:"event" yaml:"event"`
+	Time        time.Time          `json:"time" yaml:"time"`
+	Project     string             `json:"project,omitempty" yaml:"project,omitempty"`
+	BuildID     string             `json:"buildId,omitempty" yaml:"buildId,omitempty"`
+	URL         string             `json:"url,omitempty" yaml:"url,omitempty"`
+	File        string             `json:"file,omitempty" yaml:"file,omitempty"`
+	Trigger     string             `json:"trigger,omitempty" yaml:"trigger,omitempty"`
+	Listing     *projectListingDTO `json:"listing,omitempty" yaml:"listing,omitempty"`
+	Requests    []*buildRequestDTO `json:"requests,omitempty" yaml:"requests,omitempty"`
+	Projects    []string           `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Builds      []*buildInfoDTO    `json:"builds,omitempty" yaml:"builds,omitempty"`
+	Results     []*buildResultDTO  `json:"results,omitempty" yaml:"results,omitempty"`
+	Logs        []*logExcerptDTO   `json:"logs,omitempty" yaml:"logs,omitempty"`
+	Request     string             `json:"request,omitempty" yaml:"request,omitempty"`
+	Attempt     int                `json:"attempt,omitempty" yaml:"attempt,omitempty"`
+	MaxAttempts int                `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+	Delay       string             `json:"delay,omitempty" yaml:"delay,omitempty"`
+	Timeout     string             `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Error       string             `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type buildRequestDTO struct {
+	Project string `json:"project" yaml:"project"`
+	Event   string `json:"event" yaml:"event"`
+	Payload string `json:"payload" yaml:"payload"`
+}
+
+type buildInfoDTO struct {
+	Project string `json:"project" yaml:"project"`
+	BuildID string `json:"buildId" yaml:"buildId"`
+	URL     string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+type buildResultDTO struct {
+	Project  string `json:"project" yaml:"project"`
+	BuildID  string `json:"buildId,omitempty" yaml:"buildId,omitempty"`
+	Outcome  string `json:"outcome" yaml:"outcome"`
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	URL      string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+type logExcerptDTO struct {
+	Job   string   `json:"job" yaml:"job"`
+	Step  string   `json:"step" yaml:"step"`
+	Lines []string `json:"lines" yaml:"lines"`
+}
+
+func (p *encodedBuildProjectsPresenter) emit(event *buildEventDTO) {
+	event.Time = p.now().UTC()
+	writeDocument(p.out, p.marshal, event)
+}
+
+func (p *encodedBuildProjectsPresenter) ChangesBetween(lastCommit core.Hash, currentCommit core.Hash) {
+	// commits are reported in the changes event
+}
+
+func (p *encodedBuildProjectsPresenter) AllPathsTriggeredBy(changedPath string, trigger string) {
+	p.emit(&buildEventDTO{Event: eventGlobalTrigger, File: changedPath, Trigger: trigger})
+}
+
+func (p *encodedBuildProjectsPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	p.emit(&buildEventDTO{Event: eventChanges, Listing: projectListingDTOOf(listing)})
+}
+
+func (p *encodedBuildProjectsPresenter) PullRequestCommented(number int, commentURL string) {
+	// builds do not comment on pull requests
+}
+
+func (p *encodedBuildProjectsPresenter) BuildPlanned(requests []*core.BuildRequest) {
+	dtos := make([]*buildRequestDTO, len(requests))
+	for i, v := range requests {
+		dtos[i] = &buildRequestDTO{Project: v.ProjectName, Event: v.Event, Payload: v.Payload}
+	}
+	p.emit(&buildEventDTO{Event: eventPlanned, Requests: dtos})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildTriggeredFor(projectName string, buildID string, webURL string) {
+	p.emit(&buildEventDTO{Event: eventBuildTriggered, Project: projectName, BuildID: buildID, URL: webURL})
+}
+
+func (p *encodedBuildProjectsPresenter) NoBuildTriggeredFor(projectName string) {
+	p.emit(&buildEventDTO{Event: eventBuildNotTriggered, Project: projectName})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildSucceededFor(projectName string, buildID string) {
+	p.emit(&buildEventDTO{Event: eventBuildSucceeded, Project: projectName, BuildID: buildID})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildFailedFor(projectName string, buildID string) {
+	p.emit(&buildEventDTO{Event: eventBuildFailed, Project: projectName, BuildID: buildID})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildLogFor(projectName string, buildID string, excerpts []*core.LogExcerpt) {
+	dtos := make([]*logExcerptDTO, len(excerpts))
+	for i, v := range excerpts {
+		dtos[i] = &logExcerptDTO{Job: v.Job, Step: v.Step, Lines: v.Lines}
+	}
+	p.emit(&buildEventDTO{Event: eventBuildLog, Project: projectName, BuildID: buildID, Logs: dtos})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildLogError(projectName string, err error) {
+	p.emit(&buildEventDTO{Event: eventBuildLogError, Project: projectName, Error: err.Error()})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildCancelledFor(projectName string, buildID string) {
+	p.emit(&buildEventDTO{Event: eventBuildCancelled, Project: projectName, BuildID: buildID})
+}
+
+func (p *encodedBuildProjectsPresenter) RetryingFailedBuildFor(projectName string, buildID string, attempt int, maxAttempts int) {
+	p.emit(&buildEventDTO{
+		Event:       eventBuildRetrying,
+		Project:     projectName,
+		BuildID:     buildID,
+		Attempt:     attempt,
+		MaxAttempts: maxAttempts,
+	})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildSkippedFor(projectName string) {
+	p.emit(&buildEventDTO{Event: eventBuildSkipped, Project: projectName})
+}
+
+func (p *encodedBuildProjectsPresenter) WaitingFor(buildInfos []*interactor.BuildInfo) {
+	p.emit(&buildEventDTO{Event: eventWaiting, Builds: buildInfoDTOsOf(buildInfos)})
+}
+
+func (p *encodedBuildProjectsPresenter) AllBuildSucceeded(projectNames []string) {
+	p.emit(&buildEventDTO{Event: eventSucceeded, Projects: projectNames})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {
+	dtos := make([]*buildResultDTO, len(results))
+	for i, v := range results {
+		dtos[i] = &buildResultDTO{
+			Project: v.ProjectName,
+			BuildID: v.BuildID,
+			Outcome: string(v.Outcome),
+			URL:     v.WebURL,
+		}
+		if v.Duration > 0 {
+			dtos[i].Duration = v.Duration.String()
+		}
+	}
+	p.emit(&buildEventDTO{Event: eventSummary, Results: dtos})
+}
+
+func (p *encodedBuildProjectsPresenter) Timeout(waitingTime time.Duration) {
+	p.emit(&buildEventDTO{Event: eventTimeout, Timeout: waitingTime.String()})
+}
+
+func (p *encodedBuildProjectsPresenter) BuildTimedOutFor(projectName string, buildID string) {
+	p.emit(&buildEventDTO{Event: eventBuildTimedOut, Project: projectName, BuildID: buildID})
+}
+
+func (p *encodedBuildProjectsPresenter) KillingBuilds(buildInfos []*interactor.BuildInfo) {
+	p.emit(&buildEventDTO{Event: eventKilling, Builds: buildInfoDTOsOf(buildInfos)})
+}
+
+func (p *encodedBuildProjectsPresenter) KillBuildError(projectName string, err error) {
+	p.emit(&buildEventDTO{Event: eventKillError, Project: projectName, Error: err.Error()})
+}
+
+func (p *encodedBuildProjectsPresenter) NotFinishedBuildsKilled() {
+	p.emit(&buildEventDTO{Event: eventKilled})
+}
+
+func (p *encodedBuildProjectsPresenter) CommitStatusError(projectName string, err error) {
+	p.emit(&buildEventDTO{Event: eventCommitStatusError, Project: projectName, Error: err.Error()})
+}
+
+func (p *encodedBuildProjectsPresenter) Cancelled() {
+	p.emit(&buildEventDTO{Event: eventInterrupted})
+}
+
+func (p *encodedBuildProjectsPresenter) RetryingRequest(request string, attempt int, delay time.Duration, err error) {
+	p.emit(&buildEventDTO{
+		Event:   eventRetryingRequest,
+		Request: request,
+		Attempt: attempt,
+		Delay:   delay.Round(time.Millisecond).String(),
+		Error:   err.Error(),
+	})
+}
+
+func buildInfoDTOsOf(buildInfos []*interactor.BuildInfo) []*buildInfoDTO {
+	dtos := make([]*buildInfoDTO, len(buildInfos))
+	for i, v := range buildInfos {
+		dtos[i] = &buildInfoDTO{Project: v.ProjectName, BuildID: v.BuildID, URL: v.WebURL}
+	}
+	return dtos
+}
@@ -0,0 +1,86 @@
+package presenter
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestEncodedBuildProjectsPresenter(t *testing.T) {
+	now := func() time.Time { return time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC) }
+
+	Convey("Given a JSON buildProjectsPresenter", t, func() {
+		var buf bytes.Buffer
+		p := NewEncodedBuildProjectsPresenter(&buf, FormatJSON).(*encodedBuildProjectsPresenter)
+		p.now = now
+
+		Convey("When calls BuildTriggeredFor and BuildFailedFor", func() {
+			p.BuildTriggeredFor("app1", "123", "https://github.com/owner/repo/actions/runs/123")
+			p.BuildFailedFor("app1", "123")
+			got := buf.String()
+
+			Convey("It should print one JSON event per line", func() {
+				want := `{"event":"build-triggered","time":"2020-07-01T10:00:00Z","project":"app1","buildId":"123","url":"https://github.com/owner/repo/actions/runs/123"}
+{"event":"build-failed","time":"2020-07-01T10:00:00Z","project":"app1","buildId":"123"}
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls BuildSummary", func() {
+			p.BuildSummary([]*interactor.BuildResult{
+				{ProjectName: "app1", BuildID: "123", Outcome: interactor.BuildSucceeded, Duration: 90 * time.Second},
+				{ProjectName: "app2", Outcome: interactor.BuildPending},
+			})
+			got := buf.String()
+
+			Convey("It should print a summary event", func() {
+				want := `{"event":"summary","time":"2020-07-01T10:00:00Z","results":[{"project":"app1","buildId":"123","outcome":"success","duration":"1m30s"},{"project":"app2","outcome":"pending"}]}
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls KillBuildError", func() {
+			p.KillBuildError("app1", errors.New("not found"))
+			got := buf.String()
+
+			Convey("It should print the error", func() {
+				want := `{"event":"kill-error","time":"2020-07-01T10:00:00Z","project":"app1","error":"not found"}
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+
+	Convey("Given a YAML buildProjectsPresenter", t, func() {
+		var buf bytes.Buffer
+		p := NewEncodedBuildProjectsPresenter(&buf, FormatYAML).(*encodedBuildProjectsPresenter)
+		p.now = now
+
+		Convey("When calls WaitingFor and NotFinishedBuildsKilled", func() {
+			p.WaitingFor([]*interactor.BuildInfo{{ProjectName: "app1", BuildID: "123"}})
+			p.NotFinishedBuildsKilled()
+			got := buf.String()
+
+			Convey("It should print one YAML document per event", func() {
+				want := `---
+event: waiting
+time: 2020-07-01T10:00:00Z
+builds:
+- project: app1
+  buildId: "123"
+---
+event: killed
+time: 2020-07-01T10:00:00Z
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+}
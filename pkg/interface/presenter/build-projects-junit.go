@@ -0,0 +1,139 @@
+package presenter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// NewJUnitBuildProjectsPresenter creates a presenter writing a JUnit XML report to w
+// once builds are finished, with one testcase per project
+func NewJUnitBuildProjectsPresenter(w io.Writer) interactor.BuildProjectsOutput {
+	return &junitBuildProjectsPresenter{w: w, logs: make(map[string][]*core.LogExcerpt)}
+}
+
+type junitBuildProjectsPresenter struct {
+	nopBuildProjectsPresenter
+	w io.Writer
+	// excerpts of logs of failed builds, by project name
+	logs map[string][]*core.LogExcerpt
+}
+
+const junitTestSuiteName = "monorepo-toolkit build"
+
+type junitTestSuitesDTO struct {
+	XMLName    xml.Name             `xml:"testsuites"`
+	TestSuites []*junitTestSuiteDTO `xml:"testsuite"`
+}
+
+type junitTestSuiteDTO struct {
+	Name      string              `xml:"name,attr"`
+	Tests     int                 `xml:"tests,attr"`
+	Failures  int                 `xml:"failures,attr"`
+	Errors    int                 `xml:"errors,attr"`
+	Skipped   int                 `xml:"skipped,attr"`
+	Time      string              `xml:"time,attr"`
+	TestCases []*junitTestCaseDTO `xml:"testcase"`
+}
+
+type junitTestCaseDTO struct {
+	Name      string          `xml:"name,attr"`
+	ClassName string          `xml:"classname,attr"`
+	Time      string          `xml:"time,attr"`
+	Failure   *junitResultDTO `xml:"failure,omitempty"`
+	Skipped   *junitResultDTO `xml:"skipped,omitempty"`
+	SystemOut string          `xml:"system-out,omitempty"`
+}
+
+type junitResultDTO struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+func (p *junitBuildProjectsPresenter) BuildLogFor(projectName string, buildID string, excerpts []*core.LogExcerpt) {
+	p.logs[projectName] = excerpts
+}
+
+func (p *junitBuildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {
+	suite := &junitTestSuiteDTO{Name: junitTestSuiteName, TestCases: make([]*junitTestCaseDTO, len(results))}
+	var total time.Duration
+	for i, v := range results {
+		total += v.Duration
+		testCase := &junitTestCaseDTO{
+			Name:      v.ProjectName,
+			ClassName: junitTestSuiteName,
+			Time:      junitTimeOf(v.Duration),
+			SystemOut: v.WebURL,
+		}
+		switch v.Outcome {
+		case interactor.BuildSucceeded:
+		case interactor.BuildFailed:
+			testCase.Failure = &junitResultDTO{
+				Message: "Build failed",
+				Type:    string(v.Outcome),
+				Text:    p.failureTextOf(v),
+			}
+		case interactor.BuildTimedOut:
+			testCase.Failure = &junitResultDTO{
+				Message: "Build was not finished in time",
+				Type:    string(v.Outcome),
+				Text:    p.failureTextOf(v),
+			}
+		case interactor.BuildCancelled:
+			testCase.Failure = &junitResultDTO{
+				Message: "Build was cancelled",
+				Type:    string(v.Outcome),
+				Text:    p.failureTextOf(v),
+			}
+		case interactor.BuildSkipped:
+			testCase.Skipped = &junitResultDTO{Message: "Build was skipped"}
+		case interactor.BuildPending:
+			testCase.Skipped = &junitResultDTO{Message: "Build was not triggered, since builds were stopped"}
+		default:
+			testCase.Skipped = &junitResultDTO{Message: "Build was stopped before it finished"}
+		}
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
+		}
+		suite.TestCases[i] = testCase
+	}
+	suite.Tests = len(results)
+	suite.Time = junitTimeOf(total)
+
+	b, err := xml.MarshalIndent(&junitTestSuitesDTO{TestSuites: []*junitTestSuiteDTO{suite}}, "", "  ")
+	if err != nil {
+		// values are plain DTOs, it should never happen
+		fmt.Fprintf(p.w, "can't encode JUnit report: %s\n", err)
+		return
+	}
+	fmt.Fprintf(p.w, "%s%s\n", xml.Header, b)
+}
+
+// failureTextOf describes a failed build with its link and excerpts of logs
+func (p *junitBuildProjectsPresenter) failureTextOf(result *interactor.BuildResult) string {
+	lines := make([]string, 0)
+	if result.WebURL != "" {
+		lines = append(lines, result.WebURL)
+	} else if result.BuildID != "" {
+		lines = append(lines, fmt.Sprintf("Build number %s", result.BuildID))
+	}
+	for _, v := range p.logs[result.ProjectName] {
+		lines = append(lines, fmt.Sprintf("Logs of failed step '%s' in job '%s':", v.Step, v.Job))
+		lines = append(lines, v.Lines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// junitTimeOf formats a duration in seconds
+func junitTimeOf(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,64 @@
+package presenter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestJUnitBuildProjectsPresenter(t *testing.T) {
+	Convey("Given a JUnit buildProjectsPresenter", t, func() {
+		var buf bytes.Buffer
+		p := NewJUnitBuildProjectsPresenter(&buf)
+
+		Convey("When builds are reported", func() {
+			p.BuildTriggeredFor("app1", "123", "https://github.com/owner/repo/actions/runs/123")
+			p.BuildFailedFor("app2", "456")
+			p.BuildLogFor("app2", "456", []*core.LogExcerpt{
+				{Job: "build", Step: "Run tests", Lines: []string{"FAIL app2 <main>"}},
+			})
+			So(buf.String(), ShouldBeEmpty)
+
+			p.BuildSummary([]*interactor.BuildResult{
+				{
+					ProjectName: "app1",
+					BuildID:     "123",
+					Outcome:     interactor.BuildSucceeded,
+					Duration:    90 * time.Second,
+					WebURL:      "https://github.com/owner/repo/actions/runs/123",
+				},
+				{ProjectName: "app2", BuildID: "456", Outcome: interactor.BuildFailed, Duration: 1500 * time.Millisecond},
+				{ProjectName: "app3", BuildID: "789", Outcome: interactor.BuildTimedOut},
+				{ProjectName: "app4", Outcome: interactor.BuildPending},
+			})
+			got := buf.String()
+
+			Convey("It should write a JUnit report with one testcase per project", func() {
+				want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="monorepo-toolkit build" tests="4" failures="2" errors="0" skipped="1" time="91.500">
+    <testcase name="app1" classname="monorepo-toolkit build" time="90.000">
+      <system-out>https://github.com/owner/repo/actions/runs/123</system-out>
+    </testcase>
+    <testcase name="app2" classname="monorepo-toolkit build" time="1.500">
+      <failure message="Build failed" type="failed">Build number 456&#xA;Logs of failed step &#39;Run tests&#39; in job &#39;build&#39;:&#xA;FAIL app2 &lt;main&gt;</failure>
+    </testcase>
+    <testcase name="app3" classname="monorepo-toolkit build" time="0.000">
+      <failure message="Build was not finished in time" type="timed-out">Build number 789</failure>
+    </testcase>
+    <testcase name="app4" classname="monorepo-toolkit build" time="0.000">
+      <skipped message="Build was not triggered, since builds were stopped"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+}
@@ -0,0 +1,186 @@
+package presenter
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// Sink is a named presenter of a multi presenter, writing its output to a writer
+type Sink struct {
+	name   string
+	output interactor.BuildProjectsOutput
+	writer *errWriter
+	// a failed sink is not reported anymore
+	failed bool
+}
+
+// NewSink creates a sink named name, with a presenter created by newOutput writing to w
+func NewSink(name string, w io.Writer, newOutput func(w io.Writer) interactor.BuildProjectsOutput) *Sink {
+	writer := &errWriter{w: w}
+	return &Sink{name: name, output: newOutput(writer), writer: writer}
+}
+
+// call reports to the presenter of the sink, returns an error if the presenter panics
+// or fails to write its output
+func (s *Sink) call(f func(interactor.BuildProjectsOutput)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%v", r)
+		}
+	}()
+	f(s.output)
+	return s.writer.err
+}
+
+// errWriter keeps the first error of writes, and discards all writes after it
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.w.Write(p)
+	w.err = err
+	return n, err
+}
+
+// NewMultiBuildProjectsPresenter creates a presenter reporting to all given sinks in order.
+// A sink failing with an error is reported to errWriter and disabled, other sinks are not affected.
+// Calls are serialized, so it is safe to report from several goroutines even if sinks are not.
+func NewMultiBuildProjectsPresenter(errWriter io.Writer, sinks ...*Sink) interactor.BuildProjectsOutput {
+	return &multiBuildProjectsPresenter{errWriter: errWriter, sinks: sinks}
+}
+
+type multiBuildProjectsPresenter struct {
+	errWriter io.Writer
+	sinks     []*Sink
+	// guards sinks, their writers and errWriter
+	mu sync.Mutex
+}
+
+func (p *multiBuildProjectsPresenter) each(f func(interactor.BuildProjectsOutput)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, s := range p.sinks {
+		if s.failed == true {
+			continue
+		}
+		err := s.call(f)
+		if err != nil {
+			s.failed = true
+			fmt.Fprintf(p.errWriter, "WARN: Output '%s' is disabled after an error: %s\n", s.name, err)
+		}
+	}
+}
+
+func (p *multiBuildProjectsPresenter) ChangesBetween(lastCommit core.Hash, currentCommit core.Hash) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.ChangesBetween(lastCommit, currentCommit) })
+}
+
+func (p *multiBuildProjectsPresenter) AllPathsTriggeredBy(changedPath string, trigger string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.AllPathsTriggeredBy(changedPath, trigger) })
+}
+
+func (p *multiBuildProjectsPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.ProjectsListed(listing) })
+}
+
+func (p *multiBuildProjectsPresenter) PullRequestCommented(number int, commentURL string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.PullRequestCommented(number, commentURL) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildPlanned(requests []*core.BuildRequest) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildPlanned(requests) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildTriggeredFor(projectName string, buildID string, webURL string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildTriggeredFor(projectName, buildID, webURL) })
+}
+
+func (p *multiBuildProjectsPresenter) NoBuildTriggeredFor(projectName string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.NoBuildTriggeredFor(projectName) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildSucceededFor(projectName string, buildID string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildSucceededFor(projectName, buildID) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildFailedFor(projectName string, buildID string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildFailedFor(projectName, buildID) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildLogFor(projectName string, buildID string, excerpts []*core.LogExcerpt) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildLogFor(projectName, buildID, excerpts) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildLogError(projectName string, err error) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildLogError(projectName, err) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildCancelledFor(projectName string, buildID string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildCancelledFor(projectName, buildID) })
+}
+
+func (p *multiBuildProjectsPresenter) RetryingFailedBuildFor(projectName string, buildID string, attempt int, maxAttempts int) {
+	p.each(func(o interactor.BuildProjectsOutput) {
+		o.RetryingFailedBuildFor(projectName, buildID, attempt, maxAttempts)
+	})
+}
+
+func (p *multiBuildProjectsPresenter) BuildSkippedFor(projectName string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildSkippedFor(projectName) })
+}
+
+func (p *multiBuildProjectsPresenter) WaitingFor(buildInfos []*interactor.BuildInfo) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.WaitingFor(buildInfos) })
+}
+
+func (p *multiBuildProjectsPresenter) AllBuildSucceeded(projectNames []string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.AllBuildSucceeded(projectNames) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildSummary(results) })
+}
+
+func (p *multiBuildProjectsPresenter) Timeout(waitingTime time.Duration) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.Timeout(waitingTime) })
+}
+
+func (p *multiBuildProjectsPresenter) BuildTimedOutFor(projectName string, buildID string) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.BuildTimedOutFor(projectName, buildID) })
+}
+
+func (p *multiBuildProjectsPresenter) KillingBuilds(buildInfos []*interactor.BuildInfo) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.KillingBuilds(buildInfos) })
+}
+
+func (p *multiBuildProjectsPresenter) KillBuildError(projectName string, err error) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.KillBuildError(projectName, err) })
+}
+
+func (p *multiBuildProjectsPresenter) NotFinishedBuildsKilled() {
+	p.each(func(o interactor.BuildProjectsOutput) { o.NotFinishedBuildsKilled() })
+}
+
+func (p *multiBuildProjectsPresenter) CommitStatusError(projectName string, err error) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.CommitStatusError(projectName, err) })
+}
+
+func (p *multiBuildProjectsPresenter) Cancelled() {
+	p.each(func(o interactor.BuildProjectsOutput) { o.Cancelled() })
+}
+
+func (p *multiBuildProjectsPresenter) RetryingRequest(request string, attempt int, delay time.Duration, err error) {
+	p.each(func(o interactor.BuildProjectsOutput) { o.RetryingRequest(request, attempt, delay, err) })
+}
@@ -0,0 +1,101 @@
+package presenter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+type panickingBuildProjectsPresenter struct {
+	nopBuildProjectsPresenter
+}
+
+func (p panickingBuildProjectsPresenter) NoBuildTriggeredFor(projectName string) {
+	panic("unexpected project " + projectName)
+}
+
+func TestMultiBuildProjectsPresenter(t *testing.T) {
+	Convey("Given a multi buildProjectsPresenter", t, func() {
+		var out1, out2, errBuf bytes.Buffer
+		p := NewMultiBuildProjectsPresenter(
+			&errBuf,
+			NewSink("text", &out1, NewBuildProjectsPresenter),
+			NewSink("failing", failingWriter{}, NewBuildProjectsPresenter),
+			NewSink("panicking", &out2, func(w io.Writer) interactor.BuildProjectsOutput {
+				return panickingBuildProjectsPresenter{}
+			}),
+		)
+
+		Convey("When calls BuildFailedFor", func() {
+			p.BuildFailedFor("app1", "123")
+
+			Convey("It should report to all sinks and disable the failing one", func() {
+				So(out1.String(), ShouldEqual, "Build failed for project 'app1(123)'\n")
+				So(errBuf.String(), ShouldEqual, "WARN: Output 'failing' is disabled after an error: disk full\n")
+			})
+
+			Convey("When calls NoBuildTriggeredFor", func() {
+				out1.Reset()
+				errBuf.Reset()
+				p.NoBuildTriggeredFor("app2")
+
+				Convey("It should recover from the panicking sink, and keep reporting to other sinks", func() {
+					So(out1.String(), ShouldContainSubstring, "No build triggered for project 'app2'")
+					So(errBuf.String(), ShouldEqual, "WARN: Output 'panicking' is disabled after an error: unexpected project app2\n")
+				})
+
+				Convey("When calls BuildFailedFor again", func() {
+					out1.Reset()
+					errBuf.Reset()
+					p.BuildFailedFor("app3", "456")
+
+					Convey("It should report to the working sink only", func() {
+						So(out1.String(), ShouldEqual, "Build failed for project 'app3(456)'\n")
+						So(errBuf.String(), ShouldBeEmpty)
+					})
+				})
+			})
+		})
+	})
+}
+
+func TestMultiBuildProjectsPresenter_Concurrent(t *testing.T) {
+	Convey("Given a multi buildProjectsPresenter with a failing sink", t, func() {
+		var out, errBuf bytes.Buffer
+		p := NewMultiBuildProjectsPresenter(
+			&errBuf,
+			NewSink("text", &out, NewBuildProjectsPresenter),
+			NewSink("failing", failingWriter{}, NewBuildProjectsPresenter),
+		)
+
+		Convey("When calls RetryingRequest from several goroutines at once", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.RetryingRequest("getting build status", 1, time.Second, errors.New("rate limited"))
+				}()
+			}
+			wg.Wait()
+
+			Convey("It should report every call, and disable the failing sink once", func() {
+				So(bytes.Count(out.Bytes(), []byte("WARN: Failed getting build status")), ShouldEqual, 10)
+				So(errBuf.String(), ShouldEqual, "WARN: Output 'failing' is disabled after an error: disk full\n")
+			})
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// nopBuildProjectsPresenter ignores everything, presenters only interested in a few events embed it
+type nopBuildProjectsPresenter struct{}
+
+func (p nopBuildProjectsPresenter) ChangesBetween(lastCommit core.Hash, currentCommit core.Hash) {}
+
+func (p nopBuildProjectsPresenter) AllPathsTriggeredBy(changedPath string, trigger string) {}
+
+func (p nopBuildProjectsPresenter) ProjectsListed(listing *interactor.ProjectListing) {}
+
+func (p nopBuildProjectsPresenter) PullRequestCommented(number int, commentURL string) {}
+
+func (p nopBuildProjectsPresenter) BuildPlanned(requests []*core.BuildRequest) {}
+
+func (p nopBuildProjectsPresenter) BuildTriggeredFor(projectName string, buildID string, webURL string) {
+}
+
+func (p nopBuildProjectsPresenter) NoBuildTriggeredFor(projectName string) {}
+
+func (p nopBuildProjectsPresenter) BuildFailedFor(projectName string, buildID string) {}
+
+func (p nopBuildProjectsPresenter) BuildLogFor(projectName string, buildID string, excerpts []*core.LogExcerpt) {
+}
+
+func (p nopBuildProjectsPresenter) BuildLogError(projectName string, err error) {}
+
+func (p nopBuildProjectsPresenter) BuildSucceededFor(projectName string, buildID string) {}
+
+func (p nopBuildProjectsPresenter) BuildTimedOutFor(projectName string, buildID string) {}
+
+func (p nopBuildProjectsPresenter) BuildCancelledFor(projectName string, buildID string) {}
+
+func (p nopBuildProjectsPresenter) RetryingFailedBuildFor(projectName string, buildID string, attempt int, maxAttempts int) {
+}
+
+func (p nopBuildProjectsPresenter) BuildSkippedFor(projectName string) {}
+
+func (p nopBuildProjectsPresenter) WaitingFor(buildInfos []*interactor.BuildInfo) {}
+
+func (p nopBuildProjectsPresenter) AllBuildSucceeded(projectNames []string) {}
+
+func (p nopBuildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {}
+
+func (p nopBuildProjectsPresenter) Timeout(waitingTime time.Duration) {}
+
+func (p nopBuildProjectsPresenter) KillingBuilds(buildInfos []*interactor.BuildInfo) {}
+
+func (p nopBuildProjectsPresenter) KillBuildError(projectName string, err error) {}
+
+func (p nopBuildProjectsPresenter) NotFinishedBuildsKilled() {}
+
+func (p nopBuildProjectsPresenter) CommitStatusError(projectName string, err error) {}
+
+func (p nopBuildProjectsPresenter) Cancelled() {}
+
+func (p nopBuildProjectsPresenter) RetryingRequest(request string, attempt int, delay time.Duration, err error) {
+}
@@ -0,0 +1,135 @@
+package presenter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// NewStepSummaryBuildProjectsPresenter creates a presenter writing a markdown job summary
+// of GitHub Actions to w, i.e., the file at $GITHUB_STEP_SUMMARY, once builds are finished.
+// Commits are linked to the repository at repositoryURL, e.g., "https://github.com/owner/repo",
+// not linked if empty.
+func NewStepSummaryBuildProjectsPresenter(w io.Writer, repositoryURL string) interactor.BuildProjectsOutput {
+	return &stepSummaryBuildProjectsPresenter{w: w, repositoryURL: strings.TrimSuffix(repositoryURL, "/")}
+}
+
+type stepSummaryBuildProjectsPresenter struct {
+	nopBuildProjectsPresenter
+	w             io.Writer
+	repositoryURL string
+	// nil until projects are listed
+	listing *interactor.ProjectListing
+}
+
+const shortHashLength = 7
+
+func (p *stepSummaryBuildProjectsPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	p.listing = listing
+}
+
+func (p *stepSummaryBuildProjectsPresenter) BuildPlanned(requests []*core.BuildRequest) {
+	var b strings.Builder
+	p.writeChanges(&b)
+	b.WriteString("### Planned builds\n\n")
+	if len(requests) == 0 {
+		b.WriteString("Dry run, no build would be triggered.\n")
+	} else {
+		b.WriteString("Dry run, builds would be triggered with:\n\n")
+		b.WriteString("| Project | Event | Payload |\n| --- | --- | --- |\n")
+		for _, v := range requests {
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` |\n", escapeCell(v.ProjectName), escapeCell(v.Event), escapeCell(v.Payload))
+		}
+	}
+	fmt.Fprintln(p.w, b.String())
+}
+
+func (p *stepSummaryBuildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {
+	var b strings.Builder
+	p.writeChanges(&b)
+	b.WriteString("### Builds\n\n")
+	if len(results) == 0 {
+		b.WriteString("No build was triggered.\n")
+	} else {
+		b.WriteString("| Project | Outcome | Duration |\n| --- | --- | --- |\n")
+		for _, v := range results {
+			project := escapeCell(v.ProjectName)
+			if v.WebURL != "" {
+				project = fmt.Sprintf("[%s](%s)", project, v.WebURL)
+			}
+			duration := "-"
+			if v.Duration > 0 {
+				duration = v.Duration.Round(time.Second).String()
+			}
+			fmt.Fprintf(&b, "| %s | %s %s | %s |\n", project, outcomeIconOf(v.Outcome), v.Outcome, duration)
+		}
+	}
+	fmt.Fprintln(p.w, b.String())
+}
+
+// writeChanges writes the title, compared commits and changed projects
+func (p *stepSummaryBuildProjectsPresenter) writeChanges(b *strings.Builder) {
+	b.WriteString("## Monorepo builds\n\n")
+	if p.listing == nil {
+		return
+	}
+	base, head := p.listing.BaseCommit, p.listing.HeadCommit
+	if base == "" {
+		fmt.Fprintf(b, "No successful build found, all files at commit %s are considered changed.\n\n", p.commitLinkOf(head))
+	} else {
+		fmt.Fprintf(b, "Changes from commit %s to %s", p.commitLinkOf(base), p.commitLinkOf(head))
+		if p.repositoryURL != "" {
+			fmt.Fprintf(b, " ([compare](%s/compare/%s...%s))", p.repositoryURL, base, head)
+		}
+		b.WriteString(".\n\n")
+	}
+	if p.listing.GlobalTrigger != "" {
+		fmt.Fprintf(b, "All projects are considered changed, since global trigger `%s` matches.\n\n", p.listing.GlobalTrigger)
+	}
+
+	b.WriteString("### Changed projects\n\n")
+	if len(p.listing.Projects) == 0 {
+		b.WriteString("No project has changes.\n\n")
+		return
+	}
+	b.WriteString("| Project | Path | Changed files |\n| --- | --- | --- |\n")
+	for _, v := range p.listing.Projects {
+		fmt.Fprintf(b, "| %s | `%s` | %d |\n", escapeCell(v.Name), escapeCell(v.Path), len(v.Files))
+	}
+	b.WriteString("\n")
+}
+
+func (p *stepSummaryBuildProjectsPresenter) commitLinkOf(hash core.Hash) string {
+	short := string(hash)
+	if len(short) > shortHashLength {
+		short = short[:shortHashLength]
+	}
+	if p.repositoryURL == "" {
+		return fmt.Sprintf("`%s`", short)
+	}
+	return fmt.Sprintf("[`%s`](%s/commit/%s)", short, p.repositoryURL, hash)
+}
+
+func outcomeIconOf(outcome interactor.BuildOutcome) string {
+	switch outcome {
+	case interactor.BuildSucceeded:
+		return "✅"
+	case interactor.BuildFailed:
+		return "❌"
+	case interactor.BuildTimedOut:
+		return "⏱️"
+	case interactor.BuildCancelled:
+		return "🚫"
+	default:
+		return "⏭️"
+	}
+}
+
+// escapeCell escapes a text in a cell of a markdown table
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,92 @@
+package presenter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestStepSummaryBuildProjectsPresenter(t *testing.T) {
+	Convey("Given a step summary buildProjectsPresenter", t, func() {
+		var buf bytes.Buffer
+		p := NewStepSummaryBuildProjectsPresenter(&buf, "https://github.com/owner/repo/")
+
+		Convey("When builds are reported", func() {
+			p.ProjectsListed(&interactor.ProjectListing{
+				BaseCommit: "1234567890",
+				HeadCommit: "abcdefghij",
+				Projects: []*interactor.ChangedProject{
+					{Name: "app1", Path: "services/app1", Files: []string{"services/app1/main.go"}},
+					{Name: "app2", Path: "services/app2", Files: []string{"services/app2/a.go", "services/app2/b.go"}},
+				},
+			})
+			So(buf.String(), ShouldBeEmpty)
+
+			p.BuildSummary([]*interactor.BuildResult{
+				{
+					ProjectName: "app1",
+					BuildID:     "123",
+					Outcome:     interactor.BuildSucceeded,
+					Duration:    90 * time.Second,
+					WebURL:      "https://github.com/owner/repo/actions/runs/123",
+				},
+				{ProjectName: "app2", BuildID: "456", Outcome: interactor.BuildFailed},
+			})
+			got := buf.String()
+
+			Convey("It should write a markdown summary", func() {
+				want := "## Monorepo builds\n\n" +
+					"Changes from commit [`1234567`](https://github.com/owner/repo/commit/1234567890) " +
+					"to [`abcdefg`](https://github.com/owner/repo/commit/abcdefghij) " +
+					"([compare](https://github.com/owner/repo/compare/1234567890...abcdefghij)).\n\n" +
+					"### Changed projects\n\n" +
+					"| Project | Path | Changed files |\n| --- | --- | --- |\n" +
+					"| app1 | `services/app1` | 1 |\n" +
+					"| app2 | `services/app2` | 2 |\n\n" +
+					"### Builds\n\n" +
+					"| Project | Outcome | Duration |\n| --- | --- | --- |\n" +
+					"| [app1](https://github.com/owner/repo/actions/runs/123) | ✅ success | 1m30s |\n" +
+					"| app2 | ❌ failed | - |\n\n"
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When no project has changes", func() {
+			p.ProjectsListed(&interactor.ProjectListing{HeadCommit: "abcdefghij"})
+			p.BuildSummary([]*interactor.BuildResult{})
+			got := buf.String()
+
+			Convey("It should write that nothing was built", func() {
+				want := "## Monorepo builds\n\n" +
+					"No successful build found, all files at commit " +
+					"[`abcdefg`](https://github.com/owner/repo/commit/abcdefghij) are considered changed.\n\n" +
+					"### Changed projects\n\n" +
+					"No project has changes.\n\n" +
+					"### Builds\n\n" +
+					"No build was triggered.\n\n"
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When builds are planned", func() {
+			p.BuildPlanned([]*core.BuildRequest{
+				{ProjectName: "app1", Event: "build-app1", Payload: `{"job":"app1"}`},
+			})
+			got := buf.String()
+
+			Convey("It should write planned builds", func() {
+				want := "## Monorepo builds\n\n" +
+					"### Planned builds\n\n" +
+					"Dry run, builds would be triggered with:\n\n" +
+					"| Project | Event | Payload |\n| --- | --- | --- |\n" +
+					"| app1 | `build-app1` | `{\"job\":\"app1\"}` |\n\n"
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+}
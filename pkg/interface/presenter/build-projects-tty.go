@@ -0,0 +1,375 @@
+package presenter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// IsTerminal checks if f is a terminal, so that it can show a live-updating view
+func IsTerminal(f *os.File) bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// NewTTYBuildProjectsPresenter creates a presenter showing a live-updating table of builds
+// to a terminal, with other messages printed above it
+func NewTTYBuildProjectsPresenter(writer io.Writer) interactor.BuildProjectsOutput {
+	p := newTTYBuildProjectsPresenter(writer, ttyRefreshInterval, time.Now)
+	if f, ok := writer.(*os.File); ok {
+		p.width = func() int {
+			// the terminal may be resized while builds are running
+			width, _, err := terminal.GetSize(int(f.Fd()))
+			if err != nil {
+				return 0
+			}
+			return width
+		}
+	}
+	return p
+}
+
+// interval of refreshing spinners and elapsed times, disabled if zero
+const ttyRefreshInterval = 100 * time.Millisecond
+
+func newTTYBuildProjectsPresenter(writer io.Writer, refreshInterval time.Duration, now func() time.Time) *ttyBuildProjectsPresenter {
+	p := &ttyBuildProjectsPresenter{
+		writer:          writer,
+		refreshInterval: refreshInterval,
+		now:             now,
+		rowsByName:      make(map[string]*ttyRow),
+	}
+	p.buildProjectsPresenter = buildProjectsPresenter{
+		writer:  &ttyMessageWriter{p: p},
+		webURLs: make(map[string]string),
+	}
+	return p
+}
+
+type ttyBuildProjectsPresenter struct {
+	// prints messages above the table
+	buildProjectsPresenter
+
+	mu              sync.Mutex
+	writer          io.Writer
+	refreshInterval time.Duration
+	now             func() time.Time
+	// width of the terminal, rows are not truncated if nil or zero
+	width      func() int
+	rows       []*ttyRow
+	rowsByName map[string]*ttyRow
+	// number of lines of the table drawn last time
+	drawnLines int
+	frame      int
+	stop       chan struct{}
+}
+
+type ttyRow struct {
+	projectName string
+	status      string
+	webURL      string
+	startedAt   time.Time
+	// zero while the build is running
+	finishedAt time.Time
+	// known once the summary is reported
+	duration time.Duration
+}
+
+// statuses of rows, along with interactor.BuildOutcome
+const (
+	ttyStatusRunning      = "running"
+	ttyStatusRetrying     = "retrying"
+	ttyStatusNotTriggered = "not-triggered"
+)
+
+// ttyMessageWriter writes messages above the table, and redraws the table below them
+type ttyMessageWriter struct {
+	p *ttyBuildProjectsPresenter
+}
+
+func (w *ttyMessageWriter) Write(b []byte) (int, error) {
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+	w.p.clear()
+	for _, line := range strings.SplitAfter(string(b), "\n") {
+		if line != "" {
+			fmt.Fprint(w.p.writer, colorizeMessage(line))
+		}
+	}
+	w.p.draw()
+	return len(b), nil
+}
+
+func (p *ttyBuildProjectsPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	p.update(func() {
+		if listing.Joined != "" {
+			p.rowOf(listing.Joined)
+			return
+		}
+		for _, v := range listing.Projects {
+			p.rowOf(v.Name)
+		}
+	})
+}
+
+func (p *ttyBuildProjectsPresenter) BuildTriggeredFor(projectName string, buildID string, webURL string) {
+	p.update(func() {
+		p.buildProjectsPresenter.webURLs[buildID] = webURL
+		row := p.rowOf(projectName)
+		row.status = ttyStatusRunning
+		row.webURL = webURL
+		row.startedAt = p.now()
+		row.finishedAt = time.Time{}
+		p.start()
+	})
+}
+
+func (p *ttyBuildProjectsPresenter) NoBuildTriggeredFor(projectName string) {
+	p.finish(projectName, ttyStatusNotTriggered)
+	p.buildProjectsPresenter.NoBuildTriggeredFor(projectName)
+}
+
+func (p *ttyBuildProjectsPresenter) BuildSucceededFor(projectName string, buildID string) {
+	p.finish(projectName, string(interactor.BuildSucceeded))
+	p.buildProjectsPresenter.BuildSucceededFor(projectName, buildID)
+}
+
+func (p *ttyBuildProjectsPresenter) BuildTimedOutFor(projectName string, buildID string) {
+	p.finish(projectName, string(interactor.BuildTimedOut))
+	p.buildProjectsPresenter.BuildTimedOutFor(projectName, buildID)
+}
+
+func (p *ttyBuildProjectsPresenter) BuildFailedFor(projectName string, buildID string) {
+	p.finish(projectName, string(interactor.BuildFailed))
+	p.buildProjectsPresenter.BuildFailedFor(projectName, buildID)
+}
+
+func (p *ttyBuildProjectsPresenter) BuildCancelledFor(projectName string, buildID string) {
+	p.finish(projectName, string(interactor.BuildCancelled))
+	p.buildProjectsPresenter.BuildCancelledFor(projectName, buildID)
+}
+
+func (p *ttyBuildProjectsPresenter) RetryingFailedBuildFor(projectName string, buildID string, attempt int, maxAttempts int) {
+	p.update(func() {
+		p.rowOf(projectName).status = fmt.Sprintf("%s (%d/%d)", ttyStatusRetrying, attempt, maxAttempts)
+	})
+	p.buildProjectsPresenter.RetryingFailedBuildFor(projectName, buildID, attempt, maxAttempts)
+}
+
+func (p *ttyBuildProjectsPresenter) BuildSkippedFor(projectName string) {
+	p.finish(projectName, string(interactor.BuildSkipped))
+	p.buildProjectsPresenter.BuildSkippedFor(projectName)
+}
+
+func (p *ttyBuildProjectsPresenter) WaitingFor(buildInfos []*interactor.BuildInfo) {
+	// the table shows builds being waited for, and it is refreshed on every check
+	p.update(func() {})
+}
+
+func (p *ttyBuildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {
+	p.update(func() {
+		for _, v := range results {
+			row := p.rowOf(v.ProjectName)
+			row.status = string(v.Outcome)
+			row.duration = v.Duration
+			if v.WebURL != "" {
+				row.webURL = v.WebURL
+			}
+		}
+		if p.stop != nil {
+			close(p.stop)
+			p.stop = nil
+		}
+	})
+}
+
+// update updates rows and redraws the table
+func (p *ttyBuildProjectsPresenter) update(f func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f()
+	p.clear()
+	p.draw()
+}
+
+// finish marks a build of a project as finished with status
+func (p *ttyBuildProjectsPresenter) finish(projectName string, status string) {
+	p.update(func() {
+		row := p.rowOf(projectName)
+		row.status = status
+		row.finishedAt = p.now()
+	})
+}
+
+// rowOf returns the row of a project, a row is added if not exists
+func (p *ttyBuildProjectsPresenter) rowOf(projectName string) *ttyRow {
+	row, ok := p.rowsByName[projectName]
+	if !ok {
+		row = &ttyRow{projectName: projectName, status: string(interactor.BuildPending)}
+		p.rows = append(p.rows, row)
+		p.rowsByName[projectName] = row
+	}
+	return row
+}
+
+// start starts refreshing spinners and elapsed times until the summary is reported
+func (p *ttyBuildProjectsPresenter) start() {
+	if p.stop != nil || p.refreshInterval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	p.stop = stop
+	go func() {
+		ticker := time.NewTicker(p.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				p.update(func() { p.frame++ })
+			}
+		}
+	}()
+}
+
+// clear erases the table drawn last time
+func (p *ttyBuildProjectsPresenter) clear() {
+	if p.drawnLines > 0 {
+		// move the cursor up to the first line of the table, and erase down to the end
+		fmt.Fprintf(p.writer, "\x1b[%dA\x1b[J", p.drawnLines)
+	}
+	p.drawnLines = 0
+}
+
+var ttySpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+func (p *ttyBuildProjectsPresenter) draw() {
+	if len(p.rows) == 0 {
+		return
+	}
+	header := []string{"PROJECT", "STATUS", "ELAPSED", "RUN"}
+	cells := [][]string{header}
+	for _, row := range p.rows {
+		status := row.status
+		if status == ttyStatusRunning {
+			status = fmt.Sprintf("%s %s", ttySpinnerFrames[p.frame%len(ttySpinnerFrames)], status)
+		}
+		cells = append(cells, []string{row.projectName, status, p.elapsedOf(row), row.webURL})
+	}
+	widths := make([]int, len(header))
+	for _, line := range cells {
+		for i, cell := range line {
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	maxWidth := 0
+	if p.width != nil {
+		maxWidth = p.width()
+	}
+	// the status column, which is colored
+	statusStart := widths[0] + 2
+	statusEnd := statusStart + widths[1] + 2
+	for i, line := range cells {
+		var b strings.Builder
+		for j, cell := range line {
+			b.WriteString(cell)
+			if j < len(line)-1 {
+				b.WriteString(strings.Repeat(" ", widths[j]-len([]rune(cell))+2))
+			}
+		}
+		runes := []rune(strings.TrimRight(b.String(), " "))
+		if maxWidth > 0 && len(runes) > maxWidth {
+			// a wrapped row takes more lines than the ones erased by clear
+			runes = runes[:maxWidth]
+		}
+		text := string(runes)
+		if i > 0 && len(runes) > statusStart {
+			end := statusEnd
+			if end > len(runes) {
+				end = len(runes)
+			}
+			text = string(runes[:statusStart]) +
+				colorize(colorOfStatus(p.rows[i-1].status), string(runes[statusStart:end])) +
+				string(runes[end:])
+		}
+		fmt.Fprintln(p.writer, text)
+	}
+	p.drawnLines = len(cells)
+}
+
+func (p *ttyBuildProjectsPresenter) elapsedOf(row *ttyRow) string {
+	switch {
+	case row.duration > 0:
+		return row.duration.Round(time.Second).String()
+	case row.startedAt.IsZero():
+		return "-"
+	case row.finishedAt.IsZero():
+		return p.now().Sub(row.startedAt).Round(time.Second).String()
+	default:
+		return row.finishedAt.Sub(row.startedAt).Round(time.Second).String()
+	}
+}
+
+// ANSI colors
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+	colorGray   = "\x1b[90m"
+)
+
+func colorize(color string, s string) string {
+	if color == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+func colorOfStatus(status string) string {
+	switch {
+	case status == string(interactor.BuildSucceeded):
+		return colorGreen
+	case status == string(interactor.BuildFailed),
+		status == string(interactor.BuildTimedOut),
+		status == string(interactor.BuildCancelled):
+		return colorRed
+	case status == ttyStatusRunning:
+		return colorCyan
+	case status == string(interactor.BuildPending):
+		return colorGray
+	default:
+		return colorYellow
+	}
+}
+
+// colorizeMessage colors a line of a message by its kind
+func colorizeMessage(line string) string {
+	switch {
+	case strings.HasPrefix(line, "WARN:"):
+		return colorize(colorYellow, "WARN:") + strings.TrimPrefix(line, "WARN:")
+	case strings.HasPrefix(line, "Build failed"), strings.HasPrefix(line, "Timeout!"):
+		return colorize(colorRed, strings.TrimSuffix(line, "\n")) + "\n"
+	case strings.HasPrefix(line, "Build successful"):
+		return colorize(colorGreen, strings.TrimSuffix(line, "\n")) + "\n"
+	default:
+		return line
+	}
+}
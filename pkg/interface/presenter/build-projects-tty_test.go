@@ -0,0 +1,97 @@
+package presenter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestTTYBuildProjectsPresenter(t *testing.T) {
+	Convey("Given a TTY buildProjectsPresenter", t, func() {
+		var buf bytes.Buffer
+		now := time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC)
+		p := newTTYBuildProjectsPresenter(&buf, 0, func() time.Time { return now })
+
+		Convey("When projects are listed", func() {
+			p.ProjectsListed(&interactor.ProjectListing{
+				Projects: []*interactor.ChangedProject{
+					{Name: "app1", Path: "services/app1"},
+					{Name: "app2", Path: "services/app2"},
+				},
+			})
+			got := buf.String()
+
+			Convey("It should draw a table of pending builds", func() {
+				want := "PROJECT  STATUS   ELAPSED  RUN\n" +
+					"app1     \x1b[90mpending  \x1b[0m-\n" +
+					"app2     \x1b[90mpending  \x1b[0m-\n"
+				So(got, ShouldEqual, want)
+			})
+
+			Convey("When a build is triggered and checked later", func() {
+				buf.Reset()
+				p.BuildTriggeredFor("app1", "123", "https://github.com/owner/repo/actions/runs/123")
+				now = now.Add(90 * time.Second)
+				buf.Reset()
+				p.WaitingFor([]*interactor.BuildInfo{{ProjectName: "app1", BuildID: "123"}})
+				got := buf.String()
+
+				Convey("It should redraw the table with elapsed time", func() {
+					want := "\x1b[3A\x1b[J" +
+						"PROJECT  STATUS     ELAPSED  RUN\n" +
+						"app1     \x1b[36m⠋ running  \x1b[0m1m30s    https://github.com/owner/repo/actions/runs/123\n" +
+						"app2     \x1b[90mpending    \x1b[0m-\n"
+					So(got, ShouldEqual, want)
+				})
+			})
+
+			Convey("When a build is triggered, on a narrow terminal", func() {
+				p.width = func() int { return 30 }
+				buf.Reset()
+				p.BuildTriggeredFor("app1", "123", "https://github.com/owner/repo/actions/runs/123")
+				got := buf.String()
+
+				Convey("It should truncate rows to the width of the terminal, so that none is wrapped", func() {
+					want := "\x1b[3A\x1b[J" +
+						"PROJECT  STATUS     ELAPSED  R\n" +
+						"app1     \x1b[36m⠋ running  \x1b[0m0s       h\n" +
+						"app2     \x1b[90mpending    \x1b[0m-\n"
+					So(got, ShouldEqual, want)
+				})
+			})
+
+			Convey("When a build failed", func() {
+				p.BuildTriggeredFor("app2", "456", "")
+				buf.Reset()
+				p.BuildFailedFor("app2", "456")
+				got := buf.String()
+
+				Convey("It should print the message above the table", func() {
+					So(got, ShouldContainSubstring, "\x1b[31mBuild failed for project 'app2(456)'\x1b[0m\n")
+					So(got, ShouldEndWith, "app2     \x1b[31mfailed   \x1b[0m0s\n")
+				})
+			})
+
+			Convey("When the summary is reported", func() {
+				buf.Reset()
+				p.BuildSummary([]*interactor.BuildResult{
+					{ProjectName: "app1", BuildID: "123", Outcome: interactor.BuildSucceeded, Duration: time.Minute},
+					{ProjectName: "app2", Outcome: interactor.BuildPending},
+				})
+				got := buf.String()
+
+				Convey("It should draw the final table", func() {
+					want := "\x1b[3A\x1b[J" +
+						"PROJECT  STATUS   ELAPSED  RUN\n" +
+						"app1     \x1b[32msuccess  \x1b[0m1m0s\n" +
+						"app2     \x1b[90mpending  \x1b[0m-\n"
+					So(got, ShouldEqual, want)
+				})
+			})
+		})
+	})
+}
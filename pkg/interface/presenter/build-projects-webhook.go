@@ -0,0 +1,284 @@
+package presenter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// Formats of webhook messages
+const (
+	WebhookSlack      = "slack"
+	WebhookTeams      = "teams"
+	WebhookGoogleChat = "google-chat"
+)
+
+type WebhookConfig struct {
+	// incoming webhook URL
+	URL string
+	// one of Webhook* formats, i.e., the name of the template of request bodies
+	Format string
+	// templates overriding default ones, e.g.,
+	// `{{ define "summary" }}...{{ end }}` for the text of the summary
+	Template string
+	// post an update per finished project before the summary,
+	// in the same thread if supported by the format, i.e., Google Chat
+	Threaded bool
+}
+
+// defaultWebhookTemplates renders messages in two steps. The text of a message is rendered by
+// "summary" or "update", and then it is put into a request body by the template named by a format.
+const defaultWebhookTemplates = `
+{{- define "summary" -}}
+{{ .Title }}
+{{- range .Results }}
+• {{ .Project }}: {{ .Outcome }}{{ with .Duration }} in {{ . }}{{ end }}{{ with .URL }} {{ . }}{{ end }}
+{{- end }}
+{{- end }}
+
+{{- define "update" -}}
+{{ with .Result }}{{ .Project }}: {{ .Outcome }}{{ with .URL }} {{ . }}{{ end }}{{ end }}
+{{- end }}
+
+{{- define "slack" -}}
+{"text": {{ json .Text }}}
+{{- end }}
+
+{{- define "teams" -}}
+{"@type": "MessageCard", "@context": "https://schema.org/extensions", "themeColor": {{ if .Succeeded }}"2EB67D"{{ else }}"E01E5A"{{ end }}, "summary": {{ json .Title }}, "text": {{ json .Text }}}
+{{- end }}
+
+{{- define "google-chat" -}}
+{"text": {{ json .Text }}{{ with .ThreadKey }}, "thread": {"threadKey": {{ json . }}}{{ end }}}
+{{- end }}
+`
+
+// timeout of posting a message
+const webhookTimeout = 10 * time.Second
+
+// NewWebhookBuildProjectsPresenter creates a presenter posting a summary of builds to an incoming
+// webhook once builds are finished, errors of posting are printed to writer
+func NewWebhookBuildProjectsPresenter(client *http.Client, config WebhookConfig, writer io.Writer) (interactor.BuildProjectsOutput, error) {
+	tmpl := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	})
+	tmpl, err := tmpl.Parse(defaultWebhookTemplates)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't parse default webhook templates")
+	}
+	if config.Template != "" {
+		tmpl, err = tmpl.Parse(config.Template)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't parse webhook template")
+		}
+	}
+	if tmpl.Lookup(config.Format) == nil {
+		return nil, errors.Errorf(`unknown webhook format "%s"`, config.Format)
+	}
+	return &webhookBuildProjectsPresenter{
+		client:  client,
+		config:  config,
+		tmpl:    tmpl,
+		writer:  writer,
+		webURLs: make(map[string]string),
+	}, nil
+}
+
+type webhookBuildProjectsPresenter struct {
+	nopBuildProjectsPresenter
+	client *http.Client
+	config WebhookConfig
+	tmpl   *template.Template
+	writer io.Writer
+	// key of the thread of messages, empty until projects are listed
+	threadKey string
+	// web URLs of triggered builds, by project name
+	webURLs map[string]string
+}
+
+// webhookResult is a result of a project in templates
+type webhookResult struct {
+	Project  string
+	Outcome  string
+	Duration string
+	URL      string
+}
+
+// webhookMessage is the data of templates
+type webhookMessage struct {
+	Title     string
+	Succeeded bool
+	// results of all projects, for "summary"
+	Results []*webhookResult
+	// results of failed, timed-out and cancelled projects, for "summary"
+	Failed []*webhookResult
+	// result of a finished project, for "update"
+	Result *webhookResult
+	// empty if not threaded
+	ThreadKey string
+	// rendered by "summary" or "update", for templates of formats
+	Text string
+}
+
+func (p *webhookBuildProjectsPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	if p.config.Threaded == true {
+		p.threadKey = fmt.Sprintf("monorepo-toolkit-%s", listing.HeadCommit)
+	}
+}
+
+func (p *webhookBuildProjectsPresenter) BuildTriggeredFor(projectName string, buildID string, webURL string) {
+	p.webURLs[projectName] = webURL
+}
+
+func (p *webhookBuildProjectsPresenter) BuildSucceededFor(projectName string, buildID string) {
+	p.update(projectName, interactor.BuildSucceeded)
+}
+
+func (p *webhookBuildProjectsPresenter) BuildTimedOutFor(projectName string, buildID string) {
+	p.update(projectName, interactor.BuildTimedOut)
+}
+
+func (p *webhookBuildProjectsPresenter) BuildFailedFor(projectName string, buildID string) {
+	p.update(projectName, interactor.BuildFailed)
+}
+
+func (p *webhookBuildProjectsPresenter) BuildCancelledFor(projectName string, buildID string) {
+	p.update(projectName, interactor.BuildCancelled)
+}
+
+func (p *webhookBuildProjectsPresenter) BuildSkippedFor(projectName string) {
+	p.update(projectName, interactor.BuildSkipped)
+}
+
+// update posts an update of a finished project, if threaded
+func (p *webhookBuildProjectsPresenter) update(projectName string, outcome interactor.BuildOutcome) {
+	if p.config.Threaded != true {
+		return
+	}
+	p.post("update", &webhookMessage{
+		Title:     fmt.Sprintf("Build %s for %s", outcome, projectName),
+		Succeeded: outcome == interactor.BuildSucceeded,
+		Result: &webhookResult{
+			Project: projectName,
+			Outcome: string(outcome),
+			URL:     p.webURLs[projectName],
+		},
+		ThreadKey: p.threadKey,
+	})
+}
+
+func (p *webhookBuildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {
+	if len(results) == 0 {
+		// nothing was built, nothing to notify
+		return
+	}
+	message := &webhookMessage{Succeeded: true, ThreadKey: p.threadKey}
+	// pending or still running after builds are stopped
+	notFinished := 0
+	for _, v := range results {
+		result := &webhookResult{Project: v.ProjectName, Outcome: string(v.Outcome), URL: v.WebURL}
+		if v.Duration > 0 {
+			result.Duration = v.Duration.Round(time.Second).String()
+		}
+		message.Results = append(message.Results, result)
+		switch v.Outcome {
+		case interactor.BuildSucceeded, interactor.BuildSkipped:
+			// finished without failures
+		case interactor.BuildFailed, interactor.BuildTimedOut, interactor.BuildCancelled:
+			message.Failed = append(message.Failed, result)
+			message.Succeeded = false
+		default:
+			notFinished++
+			message.Succeeded = false
+		}
+	}
+	switch {
+	case message.Succeeded == true:
+		message.Title = fmt.Sprintf("Build succeeded for %d project(s)", len(results))
+	case notFinished == 0:
+		message.Title = fmt.Sprintf("Build failed for %d of %d project(s)", len(message.Failed), len(results))
+	case len(message.Failed) == 0:
+		message.Title = fmt.Sprintf("Build not finished for %d of %d project(s)", notFinished, len(results))
+	default:
+		message.Title = fmt.Sprintf(
+			"Build failed for %d and not finished for %d of %d project(s)",
+			len(message.Failed),
+			notFinished,
+			len(results),
+		)
+	}
+	p.post("summary", message)
+}
+
+// post renders the text of a message by a template, and posts it in the configured format
+func (p *webhookBuildProjectsPresenter) post(textTemplate string, message *webhookMessage) {
+	err := p.postMessage(textTemplate, message)
+	if err != nil {
+		fmt.Fprintf(p.writer, "WARN: Can't post to webhook: %s\n", err)
+	}
+}
+
+func (p *webhookBuildProjectsPresenter) postMessage(textTemplate string, message *webhookMessage) error {
+	var text strings.Builder
+	err := p.tmpl.ExecuteTemplate(&text, textTemplate, message)
+	if err != nil {
+		return errors.Wrapf(err, `can't render template "%s"`, textTemplate)
+	}
+	message.Text = text.String()
+	var body bytes.Buffer
+	err = p.tmpl.ExecuteTemplate(&body, p.config.Format, message)
+	if err != nil {
+		return errors.Wrapf(err, `can't render template "%s"`, p.config.Format)
+	}
+
+	postURL := p.config.URL
+	if p.config.Format == WebhookGoogleChat && message.ThreadKey != "" {
+		postURL, err = withQuery(postURL, "messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+		if err != nil {
+			return err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, &body)
+	if err != nil {
+		return errors.Wrap(err, "can't create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "can't send request")
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
+		return errors.Errorf("unexpected status %s: %s", res.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+func withQuery(rawURL string, key string, value string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrap(err, "can't parse webhook URL")
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
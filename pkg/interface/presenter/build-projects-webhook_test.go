@@ -0,0 +1,171 @@
+package presenter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+type webhookRequest struct {
+	query string
+	body  string
+}
+
+func TestWebhookBuildProjectsPresenter(t *testing.T) {
+	Convey("Given a local webhook server", t, func() {
+		requests := make([]*webhookRequest, 0)
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, &webhookRequest{query: r.URL.RawQuery, body: string(b)})
+			w.WriteHeader(status)
+			w.Write([]byte("invalid_payload"))
+		}))
+		defer server.Close()
+
+		results := []*interactor.BuildResult{
+			{
+				ProjectName: "app1",
+				BuildID:     "123",
+				Outcome:     interactor.BuildSucceeded,
+				Duration:    90 * time.Second,
+				WebURL:      "https://github.com/owner/repo/actions/runs/123",
+			},
+			{ProjectName: "app2", BuildID: "456", Outcome: interactor.BuildFailed},
+		}
+		var buf bytes.Buffer
+
+		Convey("Given a Slack webhook presenter", func() {
+			p, err := NewWebhookBuildProjectsPresenter(server.Client(), WebhookConfig{URL: server.URL, Format: WebhookSlack}, &buf)
+			So(err, ShouldBeNil)
+
+			Convey("When builds are finished", func() {
+				p.BuildFailedFor("app2", "456")
+				p.BuildSummary(results)
+
+				Convey("It should post the summary only", func() {
+					So(requests, ShouldHaveLength, 1)
+					want := `{"text": "Build failed for 1 of 2 project(s)\n• app1: success in 1m30s https://github.com/owner/repo/actions/runs/123\n• app2: failed"}`
+					So(requests[0].body, ShouldEqual, want)
+					So(buf.String(), ShouldBeEmpty)
+				})
+			})
+
+			Convey("When builds are stopped before some are finished", func() {
+				p.BuildSummary(append(results,
+					&interactor.BuildResult{ProjectName: "app3", BuildID: "789", Outcome: interactor.BuildRunning},
+					&interactor.BuildResult{ProjectName: "app4", Outcome: interactor.BuildPending},
+				))
+
+				Convey("It should count projects not finished in the title", func() {
+					So(requests, ShouldHaveLength, 1)
+					So(requests[0].body, ShouldStartWith, `{"text": "Build failed for 1 and not finished for 2 of 4 project(s)\n`)
+				})
+			})
+
+			Convey("When builds are stopped before any is triggered", func() {
+				p.BuildSummary([]*interactor.BuildResult{
+					{ProjectName: "app1", Outcome: interactor.BuildPending},
+					{ProjectName: "app2", Outcome: interactor.BuildPending},
+				})
+
+				Convey("It should not tell builds failed", func() {
+					So(requests, ShouldHaveLength, 1)
+					So(requests[0].body, ShouldStartWith, `{"text": "Build not finished for 2 of 2 project(s)\n`)
+				})
+			})
+
+			Convey("When no project was built", func() {
+				p.BuildSummary([]*interactor.BuildResult{})
+
+				Convey("It should post nothing", func() {
+					So(requests, ShouldBeEmpty)
+				})
+			})
+
+			Convey("When the webhook fails", func() {
+				status = http.StatusBadRequest
+				p.BuildSummary(results)
+
+				Convey("It should print a warning", func() {
+					So(buf.String(), ShouldEqual, "WARN: Can't post to webhook: unexpected status 400 Bad Request: invalid_payload\n")
+				})
+			})
+		})
+
+		Convey("Given a threaded Google Chat webhook presenter", func() {
+			p, err := NewWebhookBuildProjectsPresenter(
+				server.Client(),
+				WebhookConfig{URL: server.URL + "?key=secret", Format: WebhookGoogleChat, Threaded: true},
+				&buf,
+			)
+			So(err, ShouldBeNil)
+
+			Convey("When builds are finished", func() {
+				p.ProjectsListed(&interactor.ProjectListing{HeadCommit: "abc"})
+				p.BuildTriggeredFor("app2", "456", "https://github.com/owner/repo/actions/runs/456")
+				p.BuildFailedFor("app2", "456")
+				p.BuildSummary(results)
+
+				Convey("It should post an update and the summary in the same thread", func() {
+					So(requests, ShouldHaveLength, 2)
+					So(requests[0].query, ShouldEqual, "key=secret&messageReplyOption=REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+					So(requests[0].body, ShouldEqual, `{"text": "app2: failed https://github.com/owner/repo/actions/runs/456", "thread": {"threadKey": "monorepo-toolkit-abc"}}`)
+					So(requests[1].body, ShouldStartWith, `{"text": "Build failed for 1 of 2 project(s)\n`)
+					So(requests[1].body, ShouldEndWith, `"thread": {"threadKey": "monorepo-toolkit-abc"}}`)
+				})
+			})
+
+			Convey("When builds are succeeded and timed out", func() {
+				p.ProjectsListed(&interactor.ProjectListing{HeadCommit: "abc"})
+				p.BuildTriggeredFor("app1", "123", "https://github.com/owner/repo/actions/runs/123")
+				p.BuildSucceededFor("app1", "123")
+				p.BuildTimedOutFor("app3", "789")
+
+				Convey("It should post an update for every finished project", func() {
+					So(requests, ShouldHaveLength, 2)
+					So(requests[0].body, ShouldEqual, `{"text": "app1: success https://github.com/owner/repo/actions/runs/123", "thread": {"threadKey": "monorepo-toolkit-abc"}}`)
+					So(requests[1].body, ShouldEqual, `{"text": "app3: timed-out", "thread": {"threadKey": "monorepo-toolkit-abc"}}`)
+				})
+			})
+		})
+
+		Convey("Given a Teams webhook presenter with a custom template", func() {
+			p, err := NewWebhookBuildProjectsPresenter(
+				server.Client(),
+				WebhookConfig{
+					URL:      server.URL,
+					Format:   WebhookTeams,
+					Template: `{{ define "summary" }}{{ len .Failed }} failed{{ range .Failed }}, {{ .Project }}{{ end }}{{ end }}`,
+				},
+				&buf,
+			)
+			So(err, ShouldBeNil)
+
+			Convey("When builds are finished", func() {
+				p.BuildSummary(results)
+
+				Convey("It should post the summary rendered by the template", func() {
+					So(requests, ShouldHaveLength, 1)
+					want := `{"@type": "MessageCard", "@context": "https://schema.org/extensions", "themeColor": "E01E5A", "summary": "Build failed for 1 of 2 project(s)", "text": "1 failed, app2"}`
+					So(requests[0].body, ShouldEqual, want)
+				})
+			})
+		})
+
+		Convey("When creates a webhook presenter of an unknown format", func() {
+			_, err := NewWebhookBuildProjectsPresenter(server.Client(), WebhookConfig{URL: server.URL, Format: "irc"}, &buf)
+
+			Convey("It should return an error", func() {
+				So(err, ShouldBeError)
+			})
+		})
+	})
+}
@@ -4,43 +4,123 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
 	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 )
 
 func NewBuildProjectsPresenter(writer io.Writer) interactor.BuildProjectsOutput {
-	return &buildProjectsPresenter{writer: writer}
+	return &buildProjectsPresenter{writer: writer, webURLs: make(map[string]string)}
+}
+
+// NewGitHubActionsBuildProjectsPresenter creates a presenter also printing workflow commands,
+// so that GitHub Actions shows annotations for failed builds
+func NewGitHubActionsBuildProjectsPresenter(writer io.Writer) interactor.BuildProjectsOutput {
+	return &buildProjectsPresenter{writer: writer, webURLs: make(map[string]string), annotate: true}
 }
 
 type buildProjectsPresenter struct {
-	writer io.Writer
+	writer   io.Writer
+	annotate bool
+	// web URLs of triggered builds, by build ID
+	webURLs map[string]string
 }
 
 func (p *buildProjectsPresenter) Println(a ...interface{}) (n int, err error) {
 	return fmt.Fprintln(p.writer, a...)
 }
 
-func (p *buildProjectsPresenter) BuildTriggeredFor(projectName string, buildID string) {
+func (p *buildProjectsPresenter) AllPathsTriggeredBy(changedPath string, trigger string) {
+	p.Println(allPathsTriggeredByMessage(changedPath, trigger))
+}
+
+func (p *buildProjectsPresenter) ChangesBetween(lastCommit core.Hash, currentCommit core.Hash) {
+	p.Println(changesBetweenMessage(lastCommit, currentCommit))
+}
+
+func (p *buildProjectsPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	// projects are reported along with their builds
+}
+
+func (p *buildProjectsPresenter) PullRequestCommented(number int, commentURL string) {
+	// builds do not comment on pull requests
+}
+
+func (p *buildProjectsPresenter) BuildPlanned(requests []*core.BuildRequest) {
+	if len(requests) == 0 {
+		p.Println("Dry run, no build would be triggered")
+		return
+	}
+	p.Println("Dry run, builds would be triggered with:")
+	w := tabwriter.NewWriter(p.writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tEVENT\tPAYLOAD")
+	for _, v := range requests {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", v.ProjectName, v.Event, v.Payload)
+	}
+	w.Flush()
+}
+
+func (p *buildProjectsPresenter) BuildTriggeredFor(projectName string, buildID string, webURL string) {
+	if webURL == "" {
+		p.Println(
+			fmt.Sprintf("Build triggered for project '%s' with number '%s'", projectName, buildID),
+		)
+		return
+	}
+	p.webURLs[buildID] = webURL
 	p.Println(
-		fmt.Sprintf("Build triggered for project '%s' with number '%s'", projectName, buildID),
+		fmt.Sprintf("Build triggered for project '%s' with number '%s': %s", projectName, buildID, webURL),
 	)
 }
 
 func (p *buildProjectsPresenter) NoBuildTriggeredFor(projectName string) {
-	// TODO: add yellow color to "WARN"
 	p.Println(
 		fmt.Sprintf("WARN: No build triggered for project '%s'.", projectName),
 		"Please check if pipeline is defined in your build tool.",
 	)
 }
 
+func (p *buildProjectsPresenter) BuildSucceededFor(projectName string, buildID string) {
+	p.Println(fmt.Sprintf("Build succeeded for project '%s(%s)'", projectName, buildID))
+}
+
 func (p *buildProjectsPresenter) BuildFailedFor(projectName string, buildID string) {
 	p.Println(fmt.Sprintf("Build failed for project '%s(%s)'", projectName, buildID))
+	p.Annotate("error", fmt.Sprintf("Build failed for %s", projectName), p.linkOf(buildID))
+}
+
+func (p *buildProjectsPresenter) BuildLogFor(projectName string, buildID string, excerpts []*core.LogExcerpt) {
+	if len(excerpts) == 0 {
+		p.Println(fmt.Sprintf("No logs of failed steps found for project '%s(%s)'", projectName, buildID))
+		return
+	}
+	for _, v := range excerpts {
+		p.Println(fmt.Sprintf("Logs of failed step '%s' in job '%s' for project '%s(%s)':", v.Step, v.Job, projectName, buildID))
+		for _, line := range v.Lines {
+			p.Println(fmt.Sprintf("[%s] %s", projectName, line))
+		}
+	}
+}
+
+func (p *buildProjectsPresenter) BuildLogError(projectName string, err error) {
+	p.Println(fmt.Sprintf("WARN: Can't get logs for project '%s': %s", projectName, err))
+}
+
+func (p *buildProjectsPresenter) BuildCancelledFor(projectName string, buildID string) {
+	p.Println(fmt.Sprintf("Build cancelled for project '%s(%s)'", projectName, buildID))
+	p.Annotate("warning", fmt.Sprintf("Build cancelled for %s", projectName), p.linkOf(buildID))
+}
+
+func (p *buildProjectsPresenter) RetryingFailedBuildFor(projectName string, buildID string, attempt int, maxAttempts int) {
+	p.Println(
+		fmt.Sprintf("WARN: Build failed for project '%s(%s)'.", projectName, buildID),
+		fmt.Sprintf("Retrying (%d/%d)...", attempt, maxAttempts),
+	)
 }
 
 func (p *buildProjectsPresenter) BuildSkippedFor(projectName string) {
-	// TODO: add yellow color to "WARN"
 	p.Println(
 		fmt.Sprintf("WARN: Build was skipped for project '%s'.", projectName),
 		"Please check if pipeline is defined in your build tool.",
@@ -51,7 +131,9 @@ func (p *buildProjectsPresenter) WaitingFor(buildInfos []*interactor.BuildInfo)
 	buildStrs := make([]string, len(buildInfos))
 	for i, v := range buildInfos {
 		buildStrs[i] = fmt.Sprintf("%s(%s)", v.ProjectName, v.BuildID)
-
+		if v.WebURL != "" {
+			buildStrs[i] += " " + v.WebURL
+		}
 	}
 	p.Println(
 		fmt.Sprintf("Waiting for build %s...", strings.Join(buildStrs, " ")),
@@ -64,13 +146,33 @@ func (p *buildProjectsPresenter) AllBuildSucceeded(projectNames []string) {
 	)
 }
 
+func (p *buildProjectsPresenter) BuildSummary(results []*interactor.BuildResult) {
+	p.Println("Build summary:")
+	w := tabwriter.NewWriter(p.writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tBUILD\tOUTCOME\tDURATION")
+	for _, v := range results {
+		buildID := v.BuildID
+		if buildID == "" {
+			buildID = "-"
+		}
+		duration := "-"
+		if v.Duration > 0 {
+			duration = v.Duration.Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.ProjectName, buildID, v.Outcome, duration)
+	}
+	w.Flush()
+}
+
 func (p *buildProjectsPresenter) Timeout(waitingTime time.Duration) {
-	p.Println(
-		fmt.Sprintf(
-			"Timeout! Some builds were not finished withing %d seconds.",
-			waitingTime,
-		),
-	)
+	message := fmt.Sprintf("Timeout! Some builds were not finished within %s.", waitingTime)
+	p.Println(message)
+	p.Annotate("error", "Build timeout", message)
+}
+
+func (p *buildProjectsPresenter) BuildTimedOutFor(projectName string, buildID string) {
+	p.Println(fmt.Sprintf("Build timed out for project '%s(%s)'", projectName, buildID))
+	p.Annotate("error", fmt.Sprintf("Build timed out for %s", projectName), p.linkOf(buildID))
 }
 
 func (p *buildProjectsPresenter) KillingBuilds(buildInfos []*interactor.BuildInfo) {
@@ -94,6 +196,44 @@ func (p *buildProjectsPresenter) NotFinishedBuildsKilled() {
 	p.Println("All not finished builds were killed")
 }
 
-func (p *buildProjectsPresenter) ThrowError(err error) {
-	panic(err)
+func (p *buildProjectsPresenter) CommitStatusError(projectName string, err error) {
+	p.Println(fmt.Sprintf("WARN: Can't set commit status for project '%s': %s", projectName, err))
+}
+
+func (p *buildProjectsPresenter) Cancelled() {
+	p.Println("Cancelled! Stop waiting for builds.")
+}
+
+func (p *buildProjectsPresenter) RetryingRequest(request string, attempt int, delay time.Duration, err error) {
+	p.Println(
+		fmt.Sprintf("WARN: Failed %s (attempt %d): %s.", request, attempt, err),
+		fmt.Sprintf("Retrying in %s...", delay.Round(time.Millisecond)),
+	)
+}
+
+// Annotate prints a workflow command of GitHub Actions, e.g., "::error title=Build failed::message",
+// if the presenter is created for GitHub Actions
+func (p *buildProjectsPresenter) Annotate(level string, title string, message string) {
+	if p.annotate != true {
+		return
+	}
+	p.Println(
+		fmt.Sprintf("::%s title=%s::%s", level, escapeProperty(title), escapeData(message)),
+	)
+}
+
+// linkOf returns a web URL of a triggered build, or its ID if the URL is not available
+func (p *buildProjectsPresenter) linkOf(buildID string) string {
+	if webURL, ok := p.webURLs[buildID]; ok {
+		return webURL
+	}
+	return fmt.Sprintf("Build number %s", buildID)
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
 }
@@ -3,16 +3,51 @@ package presenter
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
 	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
 )
 
+func TestGitHubActionsBuildProjectsPresenter(t *testing.T) {
+	Convey("Given a buildProjectsPresenter for GitHub Actions", t, func() {
+		var buf bytes.Buffer
+		p := NewGitHubActionsBuildProjectsPresenter(&buf)
+
+		Convey("When calls BuildFailedFor for a triggered build", func() {
+			p.BuildTriggeredFor("app1", "123", "https://github.com/owner/repo/actions/runs/123")
+			buf.Reset()
+			p.BuildFailedFor("app1", "123")
+			got := buf.String()
+
+			Convey("It should print an error annotation with a link", func() {
+				want := `Build failed for project 'app1(123)'
+::error title=Build failed for app1::https://github.com/owner/repo/actions/runs/123
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls Timeout", func() {
+			p.Timeout(90 * time.Second)
+			got := buf.String()
+
+			Convey("It should print an error annotation", func() {
+				want := `Timeout! Some builds were not finished within 1m30s.
+::error title=Build timeout::Timeout! Some builds were not finished within 1m30s.
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+}
+
 func TestBuildProjectsPresenter(t *testing.T) {
 	Convey("Given a buildProjectsPresenter", t, func() {
 		var buf bytes.Buffer
-		p := &buildProjectsPresenter{writer: &buf}
+		p := NewBuildProjectsPresenter(&buf).(*buildProjectsPresenter)
 
 		Convey("When calls BuildSkippedFor", func() {
 			infos := []*interactor.BuildInfo{
@@ -24,6 +59,90 @@ func TestBuildProjectsPresenter(t *testing.T) {
 
 			Convey("It should print a correct string", func() {
 				want := `Waiting for build app1(123) app2(456)...
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls BuildTriggeredFor with a web URL", func() {
+			p.BuildTriggeredFor("app1", "123", "https://github.com/owner/repo/actions/runs/123")
+			got := buf.String()
+
+			Convey("It should print a link", func() {
+				want := `Build triggered for project 'app1' with number '123': https://github.com/owner/repo/actions/runs/123
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls WaitingFor with web URLs", func() {
+			infos := []*interactor.BuildInfo{
+				{ProjectName: "app1", BuildID: "123", WebURL: "https://github.com/owner/repo/actions/runs/123"},
+				{ProjectName: "app2", BuildID: "456"},
+			}
+			p.WaitingFor(infos)
+			got := buf.String()
+
+			Convey("It should print links", func() {
+				want := `Waiting for build app1(123) https://github.com/owner/repo/actions/runs/123 app2(456)...
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls BuildPlanned", func() {
+			requests := []*core.BuildRequest{
+				{ProjectName: "app1", Event: "build-app1", Payload: `{"job":"app1"}`},
+				{ProjectName: "app10", Event: "build-app10", Payload: `{"job":"app10"}`},
+			}
+			p.BuildPlanned(requests)
+			got := buf.String()
+
+			Convey("It should print a plan table", func() {
+				want := `Dry run, builds would be triggered with:
+PROJECT  EVENT        PAYLOAD
+app1     build-app1   {"job":"app1"}
+app10    build-app10  {"job":"app10"}
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls BuildLogFor", func() {
+			excerpts := []*core.LogExcerpt{
+				{Job: "build", Step: "Run tests", Lines: []string{"--- FAIL: TestApp", "FAIL"}},
+			}
+			p.BuildLogFor("app1", "123", excerpts)
+			got := buf.String()
+
+			Convey("It should print log lines prefixed with the project name", func() {
+				want := `Logs of failed step 'Run tests' in job 'build' for project 'app1(123)':
+[app1] --- FAIL: TestApp
+[app1] FAIL
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls BuildSummary", func() {
+			results := []*interactor.BuildResult{
+				{ProjectName: "app1", BuildID: "123", Outcome: interactor.BuildSucceeded, Duration: 90*time.Second + 400*time.Millisecond},
+				{ProjectName: "app2", BuildID: "456", Outcome: interactor.BuildFailed, Duration: 5 * time.Minute},
+				{ProjectName: "app3", Outcome: interactor.BuildSkipped},
+				{ProjectName: "app10", BuildID: "789", Outcome: interactor.BuildTimedOut},
+				{ProjectName: "app11", BuildID: "999", Outcome: interactor.BuildCancelled, Duration: time.Second},
+			}
+			p.BuildSummary(results)
+			got := buf.String()
+
+			Convey("It should print a summary table", func() {
+				want := `Build summary:
+PROJECT  BUILD  OUTCOME    DURATION
+app1     123    success    1m30s
+app2     456    failed     5m0s
+app3     -      skipped    -
+app10    789    timed-out  -
+app11    999    cancelled  1s
 `
 				So(got, ShouldEqual, want)
 			})
@@ -0,0 +1,72 @@
+package presenter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Format is an output format of presenters
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	// a matrix for strategy.matrix of GitHub Actions jobs, only for listing projects
+	FormatGitHubMatrix Format = "github-matrix"
+)
+
+var (
+	// formats of building projects
+	Formats = []Format{FormatText, FormatJSON, FormatYAML}
+	// formats of listing projects
+	ListFormats = []Format{FormatText, FormatJSON, FormatYAML, FormatGitHubMatrix}
+)
+
+// ParseFormat parses s as one of given formats
+func ParseFormat(s string, formats []Format) (Format, error) {
+	for _, f := range formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", errors.Errorf(`unknown output format "%s"`, s)
+}
+
+// marshaler encodes a value into a single document of an output format
+type marshaler func(v interface{}) ([]byte, error)
+
+func marshalerOf(format Format) marshaler {
+	switch format {
+	case FormatYAML:
+		return marshalYAML
+	default:
+		return json.Marshal
+	}
+}
+
+// marshalYAML encodes a value into a YAML document, starting with a document separator
+// so that a stream of documents can be parsed
+func marshalYAML(v interface{}) ([]byte, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("---\n"), b...), nil
+}
+
+// writeDocument writes a value as a document, followed by a new line
+func writeDocument(w io.Writer, marshal marshaler, v interface{}) {
+	b, err := marshal(v)
+	if err != nil {
+		// values are plain DTOs, it should never happen
+		fmt.Fprintf(w, "can't encode output: %s\n", err)
+		return
+	}
+	fmt.Fprintln(w, strings.TrimRight(string(b), "\n"))
+}
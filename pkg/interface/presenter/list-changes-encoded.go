@@ -0,0 +1,67 @@
+package presenter
+
+import (
+	"io"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// NewEncodedListChangesPresenter creates a presenter writing the listing of projects
+// as a single JSON or YAML document to out
+func NewEncodedListChangesPresenter(out io.Writer, format Format) interactor.ListChangesOutput {
+	return &encodedListChangesPresenter{out: out, marshal: marshalerOf(format)}
+}
+
+type encodedListChangesPresenter struct {
+	out     io.Writer
+	marshal marshaler
+}
+
+func (p *encodedListChangesPresenter) ChangesBetween(lastCommit core.Hash, currentCommit core.Hash) {
+	// commits are reported in the listing
+}
+
+func (p *encodedListChangesPresenter) AllPathsTriggeredBy(changedPath string, trigger string) {
+	// the trigger is reported in the listing
+}
+
+func (p *encodedListChangesPresenter) PullRequestCommented(number int, commentURL string) {
+	// the listing is the only document written to out
+}
+
+func (p *encodedListChangesPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	writeDocument(p.out, p.marshal, projectListingDTOOf(listing))
+}
+
+type projectListingDTO struct {
+	BaseCommit    string               `json:"baseCommit" yaml:"baseCommit"`
+	HeadCommit    string               `json:"headCommit" yaml:"headCommit"`
+	GlobalTrigger string               `json:"globalTrigger,omitempty" yaml:"globalTrigger,omitempty"`
+	Projects      []*changedProjectDTO `json:"projects" yaml:"projects"`
+	Joined        string               `json:"joined,omitempty" yaml:"joined,omitempty"`
+}
+
+type changedProjectDTO struct {
+	Name  string   `json:"name" yaml:"name"`
+	Path  string   `json:"path" yaml:"path"`
+	Files []string `json:"files" yaml:"files"`
+}
+
+func projectListingDTOOf(listing *interactor.ProjectListing) *projectListingDTO {
+	projects := make([]*changedProjectDTO, len(listing.Projects))
+	for i, p := range listing.Projects {
+		files := p.Files
+		if files == nil {
+			files = []string{}
+		}
+		projects[i] = &changedProjectDTO{Name: p.Name, Path: p.Path, Files: files}
+	}
+	return &projectListingDTO{
+		BaseCommit:    string(listing.BaseCommit),
+		HeadCommit:    string(listing.HeadCommit),
+		GlobalTrigger: listing.GlobalTrigger,
+		Projects:      projects,
+		Joined:        listing.Joined,
+	}
+}
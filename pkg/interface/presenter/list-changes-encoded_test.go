@@ -0,0 +1,71 @@
+package presenter
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestEncodedListChangesPresenter(t *testing.T) {
+	listing := &interactor.ProjectListing{
+		BaseCommit: "123",
+		HeadCommit: "456",
+		Projects: []*interactor.ChangedProject{
+			{Name: "app1", Path: "services/app1", Files: []string{"services/app1/main.go"}},
+		},
+	}
+
+	Convey("Given a JSON listChangesPresenter", t, func() {
+		var buf bytes.Buffer
+		p := NewEncodedListChangesPresenter(&buf, FormatJSON)
+
+		Convey("When calls ChangesBetween and ProjectsListed", func() {
+			p.ChangesBetween("123", "456")
+			p.ProjectsListed(listing)
+			got := buf.String()
+
+			Convey("It should print a single JSON document", func() {
+				want := `{"baseCommit":"123","headCommit":"456","projects":[{"name":"app1","path":"services/app1","files":["services/app1/main.go"]}]}
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls ProjectsListed with no projects", func() {
+			p.ProjectsListed(&interactor.ProjectListing{HeadCommit: "456"})
+			got := buf.String()
+
+			Convey("It should print an empty list of projects", func() {
+				want := `{"baseCommit":"","headCommit":"456","projects":[]}
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+
+	Convey("Given a YAML listChangesPresenter", t, func() {
+		var buf bytes.Buffer
+		p := NewEncodedListChangesPresenter(&buf, FormatYAML)
+
+		Convey("When calls ProjectsListed", func() {
+			p.ProjectsListed(listing)
+			got := buf.String()
+
+			Convey("It should print a single YAML document", func() {
+				want := `---
+baseCommit: "123"
+headCommit: "456"
+projects:
+- name: app1
+  path: services/app1
+  files:
+  - services/app1/main.go
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package presenter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// NewGitHubMatrixListChangesPresenter creates a presenter printing changed projects as a matrix
+// of GitHub Actions to out, and setting it as outputs of the step to githubOutput, i.e., the file
+// at $GITHUB_OUTPUT, if not nil. Other messages are printed to writer.
+//
+// Outputs of the step are
+//   - matrix: e.g., {"include":[{"project":"app1","path":"services/app1"}]}
+//   - has-changes: "true" or "false", a matrix with no projects is not valid for strategy.matrix,
+//     so jobs using it should be skipped by `if: needs.<job>.outputs.has-changes == 'true'`
+func NewGitHubMatrixListChangesPresenter(out io.Writer, githubOutput io.Writer, writer io.Writer) interactor.ListChangesOutput {
+	return &gitHubMatrixListChangesPresenter{
+		listChangesPresenter: listChangesPresenter{out: out, writer: writer},
+		githubOutput:         githubOutput,
+	}
+}
+
+type gitHubMatrixListChangesPresenter struct {
+	listChangesPresenter
+	githubOutput io.Writer
+}
+
+type gitHubMatrixDTO struct {
+	Include []*gitHubMatrixEntryDTO `json:"include"`
+}
+
+type gitHubMatrixEntryDTO struct {
+	Project string `json:"project"`
+	Path    string `json:"path"`
+}
+
+// ProjectsListed prints the matrix of listed projects, joined projects are not supported by a matrix
+// and always listed one by one
+func (p *gitHubMatrixListChangesPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	matrix := &gitHubMatrixDTO{Include: make([]*gitHubMatrixEntryDTO, len(listing.Projects))}
+	for i, project := range listing.Projects {
+		matrix.Include[i] = &gitHubMatrixEntryDTO{Project: project.Name, Path: project.Path}
+	}
+	writeDocument(p.out, json.Marshal, matrix)
+	if p.githubOutput == nil {
+		return
+	}
+	b, err := json.Marshal(matrix)
+	if err != nil {
+		// values are plain DTOs, it should never happen
+		p.Println(fmt.Sprintf("can't encode matrix: %s", err))
+		return
+	}
+	_, err = fmt.Fprintf(p.githubOutput, "matrix=%s\nhas-changes=%t\n", b, len(matrix.Include) > 0)
+	if err != nil {
+		p.Println(fmt.Sprintf("can't write outputs to $GITHUB_OUTPUT: %s", err))
+	}
+}
@@ -0,0 +1,67 @@
+package presenter
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestGitHubMatrixListChangesPresenter(t *testing.T) {
+	Convey("Given a listChangesPresenter for GitHub Actions matrix", t, func() {
+		var out, githubOutput, buf bytes.Buffer
+		p := NewGitHubMatrixListChangesPresenter(&out, &githubOutput, &buf)
+
+		Convey("When calls ProjectsListed", func() {
+			p.ProjectsListed(&interactor.ProjectListing{
+				Projects: []*interactor.ChangedProject{
+					{Name: "app1", Path: "services/app1"},
+					{Name: "app2", Path: "services/app2"},
+				},
+			})
+
+			Convey("It should print the matrix to out and outputs of the step", func() {
+				matrix := `{"include":[{"project":"app1","path":"services/app1"},{"project":"app2","path":"services/app2"}]}`
+				So(out.String(), ShouldEqual, matrix+"\n")
+				So(githubOutput.String(), ShouldEqual, "matrix="+matrix+"\nhas-changes=true\n")
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When calls ProjectsListed with no projects", func() {
+			p.ProjectsListed(&interactor.ProjectListing{})
+
+			Convey("It should print an empty matrix and no changes", func() {
+				So(out.String(), ShouldEqual, `{"include":[]}`+"\n")
+				So(githubOutput.String(), ShouldEqual, `matrix={"include":[]}`+"\nhas-changes=false\n")
+			})
+		})
+
+		Convey("When calls ChangesBetween", func() {
+			p.ChangesBetween("123", "456")
+
+			Convey("It should print the message to writer", func() {
+				So(buf.String(), ShouldEqual, "Listing changes from commit '123' to '456'\n")
+				So(out.String(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a listChangesPresenter for GitHub Actions matrix without $GITHUB_OUTPUT", t, func() {
+		var out, buf bytes.Buffer
+		p := NewGitHubMatrixListChangesPresenter(&out, nil, &buf)
+
+		Convey("When calls ProjectsListed", func() {
+			p.ProjectsListed(&interactor.ProjectListing{
+				Projects: []*interactor.ChangedProject{{Name: "app1", Path: "services/app1"}},
+			})
+
+			Convey("It should print the matrix to out only", func() {
+				So(out.String(), ShouldEqual, `{"include":[{"project":"app1","path":"services/app1"}]}`+"\n")
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+	})
+}
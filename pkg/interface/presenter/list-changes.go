@@ -0,0 +1,67 @@
+package presenter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+// NewListChangesPresenter creates a presenter printing projects to out, and other messages to writer
+func NewListChangesPresenter(out io.Writer, writer io.Writer) interactor.ListChangesOutput {
+	return &listChangesPresenter{out: out, writer: writer}
+}
+
+type listChangesPresenter struct {
+	out    io.Writer
+	writer io.Writer
+}
+
+func (p *listChangesPresenter) Println(a ...interface{}) (n int, err error) {
+	return fmt.Fprintln(p.writer, a...)
+}
+
+func (p *listChangesPresenter) ChangesBetween(lastCommit core.Hash, currentCommit core.Hash) {
+	p.Println(changesBetweenMessage(lastCommit, currentCommit))
+}
+
+func (p *listChangesPresenter) AllPathsTriggeredBy(changedPath string, trigger string) {
+	p.Println(allPathsTriggeredByMessage(changedPath, trigger))
+}
+
+func (p *listChangesPresenter) ProjectsListed(listing *interactor.ProjectListing) {
+	if listing.Joined != "" {
+		fmt.Fprintln(p.out, listing.Joined)
+		return
+	}
+	for _, project := range listing.Projects {
+		fmt.Fprintln(p.out, project.Name)
+	}
+}
+
+func (p *listChangesPresenter) PullRequestCommented(number int, commentURL string) {
+	if number == 0 {
+		p.Println("Not built for a pull request, no comment is posted")
+		return
+	}
+	p.Println(fmt.Sprintf("Affected projects are reported on pull request #%d, %s", number, commentURL))
+}
+
+func allPathsTriggeredByMessage(changedPath string, trigger string) string {
+	return fmt.Sprintf(
+		"All projects are considered changed, since '%s' matches global trigger '%s'",
+		changedPath,
+		trigger,
+	)
+}
+
+func changesBetweenMessage(lastCommit core.Hash, currentCommit core.Hash) string {
+	if lastCommit == "" {
+		return fmt.Sprintf(
+			"No successful build found, all files at commit '%s' are considered changed",
+			currentCommit,
+		)
+	}
+	return fmt.Sprintf("Listing changes from commit '%s' to '%s'", lastCommit, currentCommit)
+}
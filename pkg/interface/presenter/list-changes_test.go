@@ -0,0 +1,94 @@
+package presenter
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/whatthefar/monorepo-toolkit/pkg/interactor"
+)
+
+func TestListChangesPresenter(t *testing.T) {
+	Convey("Given a listChangesPresenter", t, func() {
+		var buf, out bytes.Buffer
+		p := &listChangesPresenter{out: &out, writer: &buf}
+
+		Convey("When calls ProjectsListed", func() {
+			p.ProjectsListed(&interactor.ProjectListing{
+				Projects: []*interactor.ChangedProject{
+					{Name: "app1", Path: "services/app1"},
+					{Name: "app2", Path: "services/app2"},
+				},
+			})
+
+			Convey("It should print project names to out", func() {
+				So(out.String(), ShouldEqual, "app1\napp2\n")
+				So(buf.String(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When calls ProjectsListed with joined projects", func() {
+			p.ProjectsListed(&interactor.ProjectListing{
+				Projects: []*interactor.ChangedProject{{Name: "app1", Path: "services/app1"}},
+				Joined:   "|app1|",
+			})
+
+			Convey("It should print joined projects to out", func() {
+				So(out.String(), ShouldEqual, "|app1|\n")
+			})
+		})
+
+		Convey("When calls ChangesBetween", func() {
+			p.ChangesBetween("123", "456")
+			got := buf.String()
+
+			Convey("It should print a correct string", func() {
+				want := `Listing changes from commit '123' to '456'
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls ChangesBetween with no last commit", func() {
+			p.ChangesBetween("", "456")
+			got := buf.String()
+
+			Convey("It should print a correct string", func() {
+				want := `No successful build found, all files at commit '456' are considered changed
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When calls PullRequestCommented", func() {
+			p.PullRequestCommented(12, "https://github.com/owner/repo/pull/12#issuecomment-1")
+
+			Convey("It should print a link to the comment", func() {
+				want := `Affected projects are reported on pull request #12, https://github.com/owner/repo/pull/12#issuecomment-1
+`
+				So(buf.String(), ShouldEqual, want)
+				So(out.String(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When calls PullRequestCommented not for a pull request", func() {
+			p.PullRequestCommented(0, "")
+
+			Convey("It should print nothing is commented", func() {
+				So(buf.String(), ShouldEqual, "Not built for a pull request, no comment is posted\n")
+			})
+		})
+
+		Convey("When calls AllPathsTriggeredBy", func() {
+			p.AllPathsTriggeredBy("go.mod", "go.mod")
+			got := buf.String()
+
+			Convey("It should print a correct string", func() {
+				want := `All projects are considered changed, since 'go.mod' matches global trigger 'go.mod'
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+	})
+}
@@ -14,6 +14,11 @@ const (
 	GITHUB_SHA        = "GITHUB_SHA"
 	GITHUB_REPOSITORY = "GITHUB_REPOSITORY"
 	GITHUB_EVENT_TYPE = "GITHUB_EVENT_TYPE"
+	GITHUB_HEAD_REF   = "GITHUB_HEAD_REF"
+	GITHUB_SERVER_URL = "GITHUB_SERVER_URL"
+
+	// the server of github.com, GitHub Enterprise Server sets its own in GITHUB_SERVER_URL
+	gitHubServerURLDefault = "https://github.com"
 
 	gitHubRefSeparator        = "/"
 	gitHubRepositorySeparator = "/"
@@ -27,6 +32,8 @@ func NewGitHubActionEnv() GitHubActionEnv {
 	v.BindEnv(GITHUB_SHA)
 	v.BindEnv(GITHUB_REPOSITORY)
 	v.BindEnv(GITHUB_EVENT_TYPE)
+	v.BindEnv(GITHUB_HEAD_REF)
+	v.BindEnv(GITHUB_SERVER_URL)
 	v.AllowEmptyEnv(true)
 	v.Unmarshal(env)
 	return env
@@ -38,6 +45,8 @@ type gitHubActionEnv struct {
 	GitHubSha        string `mapstructure:"GITHUB_SHA"`
 	GitHubRepository string `mapstructure:"GITHUB_REPOSITORY"`
 	GitHubEventType  string `mapstructure:"GITHUB_EVENT_TYPE"`
+	GitHubHeadRef    string `mapstructure:"GITHUB_HEAD_REF"`
+	GitHubServerURL  string `mapstructure:"GITHUB_SERVER_URL"`
 }
 
 func (e *gitHubActionEnv) Validate() error {
@@ -100,3 +109,14 @@ func (e *gitHubActionEnv) Repository() string {
 func (e *gitHubActionEnv) EventType() string {
 	return e.GitHubEventType
 }
+
+func (e *gitHubActionEnv) HeadRef() string {
+	return e.GitHubHeadRef
+}
+
+func (e *gitHubActionEnv) ServerURL() string {
+	if e.GitHubServerURL == "" {
+		return gitHubServerURLDefault
+	}
+	return strings.TrimSuffix(e.GitHubServerURL, "/")
+}
@@ -16,6 +16,7 @@ func TestNewGitHubActionEnv(t *testing.T) {
 			branch     string
 			owner      string
 			repository string
+			serverURL  string
 			isValid    bool
 		}{
 			{
@@ -25,10 +26,12 @@ func TestNewGitHubActionEnv(t *testing.T) {
 					GITHUB_SHA:        "0770df1c082d9e0e3aaf1a32ad65d8b5006964f6",
 					GITHUB_REPOSITORY: "WhatTheFar/monorepo-toolkit",
 					GITHUB_EVENT_TYPE: "build",
+					GITHUB_SERVER_URL: "https://github.example.com/",
 				},
 				branch:     "master",
 				owner:      "WhatTheFar",
 				repository: "monorepo-toolkit",
+				serverURL:  "https://github.example.com",
 				isValid:    true,
 			},
 			{
@@ -39,10 +42,13 @@ func TestNewGitHubActionEnv(t *testing.T) {
 					GITHUB_REPOSITORY: "WhatTheFar/monorepo-toolkit",
 					// empty event type
 					GITHUB_EVENT_TYPE: "",
+					// not on GitHub Enterprise Server
+					GITHUB_SERVER_URL: "",
 				},
 				branch:     "master",
 				owner:      "WhatTheFar",
 				repository: "monorepo-toolkit",
+				serverURL:  "https://github.com",
 				isValid:    true,
 			},
 			{
@@ -108,6 +114,7 @@ func TestNewGitHubActionEnv(t *testing.T) {
 				branch     = v.branch
 				owner      = v.owner
 				repository = v.repository
+				serverURL  = v.serverURL
 				isValid    = v.isValid
 			)
 			Convey(fmt.Sprintf("Case %d, given env variables", i), func() {
@@ -139,6 +146,9 @@ func TestNewGitHubActionEnv(t *testing.T) {
 						assert.Equal(t, owner, ghEnv.Owner())
 						assert.Equal(t, repository, ghEnv.Repository())
 						assert.Equal(t, env[GITHUB_EVENT_TYPE], ghEnv.EventType())
+						if serverURL != "" {
+							assert.Equal(t, serverURL, ghEnv.ServerURL())
+						}
 
 					})
 
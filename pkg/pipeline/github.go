@@ -3,13 +3,22 @@
 package pipeline
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v32/github"
@@ -20,10 +29,6 @@ import (
 	"github.com/whatthefar/monorepo-toolkit/pkg/utils"
 )
 
-const (
-	triggerBuildWaitForSecond = 5
-)
-
 type GitHubActionEnv interface {
 	Validate() error
 	Token() string
@@ -33,14 +38,67 @@ type GitHubActionEnv interface {
 	Owner() string
 	Repository() string
 	EventType() string
+	// the branch of the pull request being built, empty if not built for a pull request
+	HeadRef() string
+	// the URL of the GitHub server, e.g., "https://github.com"
+	ServerURL() string
+}
+
+// Ways of reporting statuses of projects on the current commit
+const (
+	GitHubCommitStatus = "status"
+	GitHubCheckRun     = "check-run"
+)
+
+// Ways of dispatching builds
+const (
+	// a repository_dispatch event of type "build-<project>", or GITHUB_EVENT_TYPE if set
+	GitHubRepositoryDispatch = "repository"
+	// a workflow_dispatch event of a workflow file of each project
+	GitHubWorkflowDispatch = "workflow"
+)
+
+// placeholder of a project name in DispatchWorkflow
+const projectPlaceholder = "{project}"
+
+type GitHubActionConfig struct {
+	// how statuses of projects are reported, GitHubCommitStatus or GitHubCheckRun,
+	// SetProjectStatus does nothing if empty
+	CommitStatus string
+	// how builds are dispatched, GitHubRepositoryDispatch if empty
+	Dispatch string
+	// file name of the workflow dispatched for each project with GitHubWorkflowDispatch,
+	// where "{project}" is replaced by a project name, e.g., "build-{project}.yml"
+	DispatchWorkflow string
+	// additional inputs of dispatched workflows, along with "project" and "correlation_id",
+	// values are sent as strings, typed inputs are declared by the workflow
+	DispatchInputs map[string]interface{}
+	// maximum time to wait for the run created by a dispatch event, defaultDispatchWait if zero
+	DispatchWait time.Duration
+	// maximum number of successful runs looked back for the last successful commit,
+	// and of dispatched runs looked back for links to the latest builds, defaultLookbackRuns if zero
+	LookbackRuns int
+	// event of runs considered for the last successful commit, e.g., "push", any event if empty
+	SuccessEvent string
 }
 
-func NewGitHubActionGateway(env GitHubActionEnv) core.PipelineGateway {
-	return &gitHubActionGateway{env: env}
+// NewGitHubActionGateway creates a gateway of GitHub Actions, git is used to check if commits of
+// successful runs are ancestors of the current commit
+func NewGitHubActionGateway(env GitHubActionEnv, git core.GitGateway, config GitHubActionConfig) core.PipelineGateway {
+	return &gitHubActionGateway{env: env, git: git, config: config, checkRunIDs: make(map[string]int64)}
 }
 
 type gitHubActionGateway struct {
-	env GitHubActionEnv
+	env    GitHubActionEnv
+	git    core.GitGateway
+	config GitHubActionConfig
+
+	mu sync.Mutex
+	// IDs of created check runs, by project name
+	checkRunIDs map[string]int64
+
+	// base URL of the API, the one of github.com if nil
+	baseURL *url.URL
 }
 
 func (s *gitHubActionGateway) client(ctx context.Context) *github.Client {
@@ -49,111 +107,415 @@ func (s *gitHubActionGateway) client(ctx context.Context) *github.Client {
 	)
 	tc := oauth2.NewClient(ctx, ts)
 	client := github.NewClient(tc)
+	if s.baseURL != nil {
+		client.BaseURL = s.baseURL
+	}
 	return client
 }
 
 // get hash of last succesfull build commit only commits of 'build' job are considered
+// successful runs are listed page by page from the latest, up to LookbackRuns, and runs of commits
+// not reachable from the current commit, e.g., force-pushed away, are ignored
 func (s *gitHubActionGateway) LastSuccessfulCommit(
 	ctx context.Context,
 	workflowID string,
 ) (core.Hash, error) {
 	opts := &github.ListWorkflowRunsOptions{
-		Branch: s.env.Branch(),
+		Branch:      s.env.Branch(),
+		Event:       s.config.SuccessEvent,
+		Status:      "success",
+		ListOptions: github.ListOptions{PerPage: lookbackRunsPerPage},
 	}
 	// if workflow is triggered by tag, ignore branch filter
 	if strings.HasPrefix(s.env.Ref(), "refs/tags") {
 		opts.Branch = ""
 	}
-	workflowRuns, _, err := s.client(ctx).Actions.ListWorkflowRunsByFileName(
-		ctx,
-		s.env.Owner(),
-		s.env.Repository(),
-		workflowID,
-		opts,
-	)
-	if err != nil {
-		return "", errors.Wrapf(err, "can't list workflow runs by workflow ID, %s", workflowID)
+	lookback := s.config.LookbackRuns
+	if lookback <= 0 {
+		lookback = defaultLookbackRuns
 	}
-	// Descending sort workflows by run number
-	sort.SliceStable(workflowRuns.WorkflowRuns, func(i, j int) bool {
-		return *workflowRuns.WorkflowRuns[i].RunNumber > *workflowRuns.WorkflowRuns[j].RunNumber
-	})
+	client := s.client(ctx)
+	currentCommit := s.CurrentCommit()
+	// a commit may have several successful runs, check it only once
+	checked := make(map[string]bool)
+	for listed := 0; listed < lookback; {
+		workflowRuns, resp, err := client.Actions.ListWorkflowRunsByFileName(
+			ctx,
+			s.env.Owner(),
+			s.env.Repository(),
+			workflowID,
+			opts,
+		)
+		if err != nil {
+			return "", errors.Wrapf(err, "can't list workflow runs by workflow ID, %s", workflowID)
+		}
+		runs := workflowRuns.WorkflowRuns
+		if len(runs) > lookback-listed {
+			runs = runs[:lookback-listed]
+		}
+		listed += len(runs)
+		// Descending sort workflows by run number
+		sort.SliceStable(runs, func(i, j int) bool {
+			return runs[i].GetRunNumber() > runs[j].GetRunNumber()
+		})
 
-	for _, run := range workflowRuns.WorkflowRuns {
-		if run.Conclusion != nil && *run.Conclusion == "success" {
-			return core.Hash(*run.HeadSHA), nil
+		for _, run := range runs {
+			sha := run.GetHeadSHA()
+			if run.GetConclusion() != "success" || sha == "" || checked[sha] {
+				continue
+			}
+			checked[sha] = true
+			isAncestor, err := s.git.IsAncestor(ctx, core.Hash(sha), currentCommit)
+			if err != nil {
+				return "", errors.Wrapf(err, `can't check if commit "%s" of run ID %d is an ancestor`, sha, run.GetID())
+			}
+			if isAncestor {
+				return core.Hash(sha), nil
+			}
+		}
+		if resp.NextPage == 0 || len(runs) == 0 {
+			break
 		}
+		opts.Page = resp.NextPage
 	}
 
 	return "", nil
 }
 
+const (
+	// default maximum number of successful runs looked back for the last successful commit
+	defaultLookbackRuns = 300
+	// number of runs listed per page
+	lookbackRunsPerPage = 100
+)
+
 // get hash of current commit
 func (s *gitHubActionGateway) CurrentCommit() core.Hash {
 	return core.Hash(s.env.Sha())
 }
 
-// start build of given project
-// outputs build request id
-func (s *gitHubActionGateway) TriggerBuild(ctx context.Context, projectName string) (*string, error) {
-	client := s.client(ctx)
+// describe the repository or workflow dispatch event TriggerBuild would send for given project
+func (s *gitHubActionGateway) BuildRequestFor(projectName string) (*core.BuildRequest, error) {
+	return s.buildRequestFor(projectName, newCorrelationID())
+}
+
+// buildRequestFor describes a dispatch event identified by given correlation ID
+func (s *gitHubActionGateway) buildRequestFor(projectName string, correlationID string) (*core.BuildRequest, error) {
+	if s.config.Dispatch == GitHubWorkflowDispatch {
+		return s.workflowDispatchRequestFor(projectName, correlationID)
+	}
 	eventType := s.env.EventType()
 	if eventType == "" {
 		eventType = fmt.Sprintf("build-%s", projectName)
 	}
-	payload := json.RawMessage(fmt.Sprintf(`{ "job": "%s" }`, projectName))
-	opts := github.DispatchRequestOptions{
-		EventType:     eventType,
-		ClientPayload: &payload,
+	payload, err := json.Marshal(map[string]string{"job": projectName, "correlation_id": correlationID})
+	if err != nil {
+		return nil, errors.Wrapf(err, `can't marshal client payload for project "%s"`, projectName)
+	}
+	return &core.BuildRequest{
+		ProjectName: projectName,
+		Event:       eventType,
+		Payload:     string(payload),
+	}, nil
+}
+
+// workflowDispatchRequestFor describes a workflow dispatch event on the current ref,
+// whose event is the workflow file of the project, and payload is the body of the request
+func (s *gitHubActionGateway) workflowDispatchRequestFor(projectName string, correlationID string) (*core.BuildRequest, error) {
+	// the API rejects inputs which are not strings, even for typed inputs of the workflow
+	inputs := make(map[string]string, len(s.config.DispatchInputs)+2)
+	for k, v := range s.config.DispatchInputs {
+		inputs[k] = fmt.Sprint(v)
 	}
-	now := time.Now()
-	_, _, err := client.Repositories.Dispatch(ctx, s.env.Owner(), s.env.Repository(), opts)
+	inputs["project"] = projectName
+	inputs["correlation_id"] = correlationID
+	payload, err := json.Marshal(map[string]interface{}{
+		"ref":    s.dispatchRef(),
+		"inputs": inputs,
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "can't dispatch event")
+		return nil, errors.Wrapf(err, `can't marshal workflow inputs for project "%s"`, projectName)
+	}
+	return &core.BuildRequest{
+		ProjectName: projectName,
+		Event:       workflowFileFor(s.config.DispatchWorkflow, projectName),
+		Payload:     string(payload),
+	}, nil
+}
+
+// dispatchRef returns the ref workflows are dispatched on, the branch of the pull request if built for
+// one, since a ref like "refs/pull/123/merge" is not accepted by workflow dispatch events
+func (s *gitHubActionGateway) dispatchRef() string {
+	if headRef := s.env.HeadRef(); headRef != "" {
+		return headRef
+	}
+	return s.env.Ref()
+}
+
+func workflowFileFor(pattern string, projectName string) string {
+	return strings.ReplaceAll(pattern, projectPlaceholder, projectName)
+}
+
+// newCorrelationID generates a unique ID, to find the run created by a dispatch event
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand never fails on supported platforms
+		panic(err)
 	}
-	id, err := s.getLastRepositoryDispatchRunID(ctx, projectName, now)
-	if id == nil {
+	return hex.EncodeToString(b)
+}
+
+// start build of given project
+// outputs build request id
+func (s *gitHubActionGateway) TriggerBuild(ctx context.Context, projectName string) (*string, error) {
+	client := s.client(ctx)
+	correlationID := newCorrelationID()
+	req, err := s.buildRequestFor(projectName, correlationID)
+	if err != nil {
 		return nil, err
 	}
-	return utils.StrAddr(fmt.Sprintf("%d", *id)), err
+	owner, repo := s.env.Owner(), s.env.Repository()
+	now := time.Now()
+	if s.config.Dispatch == GitHubWorkflowDispatch {
+		dispatchReq, err := client.NewRequest(
+			http.MethodPost,
+			fmt.Sprintf("repos/%s/%s/actions/workflows/%s/dispatches", owner, repo, url.PathEscape(req.Event)),
+			json.RawMessage(req.Payload),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, `can't create dispatch request of workflow "%s"`, req.Event)
+		}
+		_, err = client.Do(ctx, dispatchReq, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, `can't dispatch workflow "%s"`, req.Event)
+		}
+	} else {
+		payload := json.RawMessage(req.Payload)
+		opts := github.DispatchRequestOptions{
+			EventType:     req.Event,
+			ClientPayload: &payload,
+		}
+		_, _, err = client.Repositories.Dispatch(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't dispatch event")
+		}
+	}
+	id, err := s.findDispatchedRun(ctx, s.dispatchedRunsURLFor(req.Event), correlationID, now)
+	if err != nil {
+		// the event has been dispatched, retrying would trigger a duplicate build
+		return nil, &dispatchedError{errors.Wrapf(err, `can't find the run of "%s" dispatched for project "%s"`, req.Event, projectName)}
+	}
+	return utils.StrAddr(fmt.Sprintf("%d", id)), nil
 }
 
-func (s *gitHubActionGateway) getLastRepositoryDispatchRunID(
+type dispatchedError struct {
+	error
+}
+
+// dispatchedRunsURLFor returns the URL listing runs created by dispatch events,
+// of given workflow file with GitHubWorkflowDispatch
+func (s *gitHubActionGateway) dispatchedRunsURLFor(workflowFile string) string {
+	owner, repo := s.env.Owner(), s.env.Repository()
+	if s.config.Dispatch == GitHubWorkflowDispatch {
+		return fmt.Sprintf(
+			"repos/%s/%s/actions/workflows/%s/runs?event=workflow_dispatch",
+			owner,
+			repo,
+			url.PathEscape(workflowFile),
+		)
+	}
+	return fmt.Sprintf("repos/%s/%s/actions/runs?event=repository_dispatch", owner, repo)
+}
+
+// workflowRunsWithTitle are workflow runs along with their names,
+// which are not decoded by go-github yet
+type workflowRunsWithTitle struct {
+	WorkflowRuns []*workflowRunWithTitle `json:"workflow_runs"`
+}
+
+type workflowRunWithTitle struct {
+	github.WorkflowRun
+	Name string `json:"name"`
+	// the run name set by "run-name" of a workflow
+	DisplayTitle string `json:"display_title"`
+}
+
+const (
+	// maximum difference between clocks of the runner and GitHub, runs created before
+	// a dispatch by this are ignored
+	dispatchClockSkew = time.Minute
+	// default time to wait for the run created by a dispatch event
+	defaultDispatchWait = time.Minute
+	// interval between listings of runs while waiting for a dispatched one
+	dispatchPollInterval = 2 * time.Second
+	// number of runs listed per page
+	dispatchRunsPerPage = 100
+)
+
+// findDispatchedRun waits for the run identified by given correlation ID, which is expected in
+// the run name, e.g., by "run-name", or in the name of a step. Steps are only listed once their
+// jobs are started, so runs are listed again until the deadline.
+func (s *gitHubActionGateway) findDispatchedRun(
+	ctx context.Context,
+	runsURL string,
+	correlationID string,
+	dispatchedAt time.Time,
+) (int64, error) {
+	wait := s.config.DispatchWait
+	if wait <= 0 {
+		wait = defaultDispatchWait
+	}
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	since := dispatchedAt.Add(-dispatchClockSkew)
+	// runs whose steps have started without the correlation ID, their jobs are not listed again
+	checked := make(map[int64]bool)
+	for {
+		id, found, err := s.findRunByCorrelationID(ctx, runsURL, correlationID, since, checked)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			return id, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-deadline.C:
+			return 0, errors.Errorf(
+				`no run with correlation ID "%s" is found within %s, `+
+					`show the ID in the run name by "run-name", or in the name of a step`,
+				correlationID,
+				wait,
+			)
+		case <-time.After(dispatchPollInterval):
+		}
+	}
+}
+
+// findRunByCorrelationID lists runs created since given time, and looks for the correlation ID
+// in their names first, then in names of steps of runs not checked yet
+func (s *gitHubActionGateway) findRunByCorrelationID(
 	ctx context.Context,
-	projectName string,
-	now time.Time,
-) (*int64, error) {
+	runsURL string,
+	correlationID string,
+	since time.Time,
+	checked map[int64]bool,
+) (int64, bool, error) {
+	runs := make([]*workflowRunWithTitle, 0)
+	err := s.listRuns(ctx, runsURL, func(run *workflowRunWithTitle) bool {
+		if run.GetCreatedAt().Before(since) {
+			// runs are listed from the latest
+			return false
+		}
+		runs = append(runs, run)
+		return true
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	for _, run := range runs {
+		if hasCorrelationID(run, correlationID) {
+			return run.GetID(), true, nil
+		}
+	}
+
+	for _, run := range runs {
+		runID := run.GetID()
+		if checked[runID] {
+			continue
+		}
+		jobs, err := s.listJobs(ctx, runID)
+		if err != nil {
+			return 0, false, err
+		}
+		if hasStepNamed(jobs, correlationID) {
+			return runID, true, nil
+		}
+		if run.GetStatus() == "completed" || hasStepsListed(jobs) {
+			// steps have started without the correlation ID, the run is of another dispatch
+			checked[runID] = true
+		}
+	}
+	return 0, false, nil
+}
+
+// listJobs lists all jobs of a workflow run page by page
+func (s *gitHubActionGateway) listJobs(ctx context.Context, runID int64) ([]*github.WorkflowJob, error) {
 	client := s.client(ctx)
 	owner, repo := s.env.Owner(), s.env.Repository()
-	for i := 0; i < triggerBuildWaitForSecond; i++ {
-		opts := &github.ListWorkflowRunsOptions{
-			Event: "repository_dispatch",
-		}
-		workflowRuns, _, err := client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+	opts := &github.ListWorkflowJobsOptions{ListOptions: github.ListOptions{PerPage: dispatchRunsPerPage}}
+	jobs := make([]*github.WorkflowJob, 0)
+	for {
+		page, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, opts)
 		if err != nil {
-			return nil, errors.Wrap(err, "can't list workflow runs for a repository")
+			return nil, errors.Wrapf(err, "can't list jobs of a workflow run, ID %d", runID)
+		}
+		jobs = append(jobs, page.Jobs...)
+		if resp.NextPage == 0 {
+			return jobs, nil
 		}
+		opts.Page = resp.NextPage
+	}
+}
 
+// listRuns lists runs of given URL page by page from the latest, until visit returns false
+func (s *gitHubActionGateway) listRuns(
+	ctx context.Context,
+	runsURL string,
+	visit func(run *workflowRunWithTitle) bool,
+) error {
+	client := s.client(ctx)
+	for page := 1; page != 0; {
+		req, err := client.NewRequest(
+			http.MethodGet,
+			fmt.Sprintf("%s&per_page=%d&page=%d", runsURL, dispatchRunsPerPage, page),
+			nil,
+		)
+		if err != nil {
+			return errors.Wrap(err, "can't create request listing workflow runs")
+		}
+		workflowRuns := &workflowRunsWithTitle{}
+		resp, err := client.Do(ctx, req, workflowRuns)
+		if err != nil {
+			return errors.Wrap(err, "can't list workflow runs")
+		}
 		for _, run := range workflowRuns.WorkflowRuns {
-			if run.GetCreatedAt().Before(now) {
-				continue
-			}
-			runID := run.GetID()
-			jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, nil)
-			if err != nil {
-				return nil, errors.Wrapf(err, "can't list jobs of a workflow run, ID %d", runID)
+			if visit(run) != true {
+				return nil
 			}
-			for _, job := range jobs.Jobs {
-				re := regexp.MustCompile(fmt.Sprintf(`%s`, projectName))
-				if re.MatchString(job.GetName()) == true {
-					return &runID, nil
-				}
+		}
+		page = resp.NextPage
+	}
+	return nil
+}
+
+func hasCorrelationID(run *workflowRunWithTitle, correlationID string) bool {
+	return strings.Contains(run.DisplayTitle, correlationID) || strings.Contains(run.Name, correlationID)
+}
+
+// hasStepNamed checks if any step of given jobs has a name containing given ID,
+// steps are only listed once their jobs are started
+func hasStepNamed(jobs []*github.WorkflowJob, id string) bool {
+	for _, job := range jobs {
+		for _, step := range job.Steps {
+			if strings.Contains(step.GetName(), id) {
+				return true
 			}
 		}
+	}
+	return false
+}
 
-		time.Sleep(time.Second)
+// hasStepsListed checks if any of given jobs is started, so that its steps are listed
+func hasStepsListed(jobs []*github.WorkflowJob) bool {
+	for _, job := range jobs {
+		if len(job.Steps) > 0 {
+			return true
+		}
 	}
-	return nil, nil
+	return false
 }
 
 func getRunIDFromJobURL(url string) (int64, error) {
@@ -174,9 +536,13 @@ func getRunIDFromJobURL(url string) (int64, error) {
 	return 0, nil
 }
 
+// get link to the page of build identified by given build number
+func (s *gitHubActionGateway) BuildURL(buildID string) string {
+	return fmt.Sprintf("%s/%s/%s/actions/runs/%s", s.env.ServerURL(), s.env.Owner(), s.env.Repository(), buildID)
+}
+
 // get status of build identified by given build number
-// outputs one of: success | failed | skipped | null
-func (s *gitHubActionGateway) BuildStatus(ctx context.Context, buildID string) (*string, error) {
+func (s *gitHubActionGateway) BuildStatus(ctx context.Context, buildID string) (*core.BuildStatus, error) {
 	runID, err := strconv.ParseInt(buildID, 10, 64)
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid build ID: %s", buildID)
@@ -185,15 +551,46 @@ func (s *gitHubActionGateway) BuildStatus(ctx context.Context, buildID string) (
 	if err != nil {
 		return nil, errors.Wrapf(err, "can't get a workflow run, ID %d", runID)
 	}
-	switch workflowRun.GetConclusion() {
-	case "success":
-		return utils.StrAddr("success"), nil
-	case "failure", "cancelled":
-		return utils.StrAddr("failed"), nil
+	return buildStatusOf(workflowRun), nil
+}
+
+func buildStatusOf(run *github.WorkflowRun) *core.BuildStatus {
+	status := &core.BuildStatus{
+		State:     buildStateOf(run.GetStatus(), run.GetConclusion()),
+		StartedAt: run.GetCreatedAt().Time,
+		WebURL:    run.GetHTMLURL(),
+	}
+	if status.State.IsFinished() {
+		status.FinishedAt = run.GetUpdatedAt().Time
+	}
+	return status
+}
+
+// buildStateOf maps a status and a conclusion of a workflow run to a build state
+func buildStateOf(status string, conclusion string) core.BuildState {
+	switch status {
+	case "queued", "requested", "waiting", "pending":
+		return core.BuildStateQueued
+	case "in_progress":
+		return core.BuildStateRunning
+	case "completed":
+		// see conclusion below
+	default:
+		return core.BuildStateUnknown
+	}
+	switch conclusion {
+	case "success", "neutral":
+		return core.BuildStateSuccess
+	case "failure", "action_required", "startup_failure":
+		return core.BuildStateFailed
+	case "cancelled":
+		return core.BuildStateCancelled
 	case "skipped":
-		return utils.StrAddr("skipped"), nil
+		return core.BuildStateSkipped
+	case "timed_out":
+		return core.BuildStateTimedOut
 	default:
-		return nil, nil
+		return core.BuildStateUnknown
 	}
 }
 
@@ -206,8 +603,492 @@ func (s *gitHubActionGateway) KillBuild(ctx context.Context, buildID string) err
 	resp, err := s.client(ctx).Actions.CancelWorkflowRunByID(ctx, s.env.Owner(), s.env.Repository(), runID)
 	// go-github considers 202 Accepted status codes as an AcceptedError
 	// so, instead of checking if there is an error, we have to handle the response manually
-	if resp.StatusCode == 202 {
+	if resp != nil && resp.StatusCode == 202 {
 		return nil
 	}
 	return errors.Wrapf(err, "can't cancel a workflow run, ID %d", runID)
 }
+
+// get the last lines of logs of failed steps of a finished build, from the workflow run logs archive
+func (s *gitHubActionGateway) FailedLogTail(ctx context.Context, buildID string, lines int) ([]*core.LogExcerpt, error) {
+	runID, err := strconv.ParseInt(buildID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid build ID: %s", buildID)
+	}
+	jobs, err := s.listJobs(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	failed := failedStepsOf(jobs)
+	if len(failed) == 0 {
+		return []*core.LogExcerpt{}, nil
+	}
+
+	client := s.client(ctx)
+	owner, repo := s.env.Owner(), s.env.Repository()
+
+	logsURL, _, err := client.Actions.GetWorkflowRunLogs(ctx, owner, repo, runID, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't get logs url of a workflow run, ID %d", runID)
+	}
+	archive, err := download(ctx, logsURL.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't download logs of a workflow run, ID %d", runID)
+	}
+	return tailOfSteps(archive, failed, lines)
+}
+
+// prefix of names of statuses and check runs, followed by a project name
+const projectStatusPrefix = "monorepo/"
+
+// create or update the status of given project on the current commit,
+// as a commit status or a check run named "monorepo/<project>"
+func (s *gitHubActionGateway) SetProjectStatus(ctx context.Context, projectName string, status *core.ProjectStatus) error {
+	switch s.config.CommitStatus {
+	case GitHubCommitStatus:
+		_, _, err := s.client(ctx).Repositories.CreateStatus(
+			ctx,
+			s.env.Owner(),
+			s.env.Repository(),
+			s.env.Sha(),
+			repoStatusOf(projectName, status),
+		)
+		return errors.Wrapf(err, `can't create commit status for project "%s"`, projectName)
+	case GitHubCheckRun:
+		return s.setCheckRun(ctx, projectName, status)
+	default:
+		return nil
+	}
+}
+
+func repoStatusOf(projectName string, status *core.ProjectStatus) *github.RepoStatus {
+	// commit statuses have no neutral state, it is a success not to block merging
+	state := string(status.State)
+	if status.State == core.CommitStateNeutral {
+		state = string(core.CommitStateSuccess)
+	}
+	repoStatus := &github.RepoStatus{
+		State:       github.String(state),
+		Description: github.String(truncate(status.Description, maxStatusDescriptionLength)),
+		Context:     github.String(projectStatusPrefix + projectName),
+	}
+	if status.TargetURL != "" {
+		repoStatus.TargetURL = github.String(status.TargetURL)
+	}
+	return repoStatus
+}
+
+// maximum length of descriptions of commit statuses
+const maxStatusDescriptionLength = 140
+
+func truncate(s string, length int) string {
+	r := []rune(s)
+	if len(r) <= length {
+		return s
+	}
+	return string(r[:length-1]) + "…"
+}
+
+// setCheckRun creates a check run of a project, and updates it afterward
+func (s *gitHubActionGateway) setCheckRun(ctx context.Context, projectName string, status *core.ProjectStatus) error {
+	client := s.client(ctx)
+	owner, repo := s.env.Owner(), s.env.Repository()
+	name := projectStatusPrefix + projectName
+	checkStatus, conclusion := checkRunStatusOf(status.State)
+	output := &github.CheckRunOutput{
+		Title:   github.String(status.Description),
+		Summary: github.String(status.Description),
+	}
+	var detailsURL *string
+	if status.TargetURL != "" {
+		detailsURL = github.String(status.TargetURL)
+	}
+	var completedAt *github.Timestamp
+	if conclusion != nil {
+		completedAt = &github.Timestamp{Time: time.Now()}
+	}
+
+	s.mu.Lock()
+	id, ok := s.checkRunIDs[projectName]
+	s.mu.Unlock()
+	if ok {
+		_, _, err := client.Checks.UpdateCheckRun(ctx, owner, repo, id, github.UpdateCheckRunOptions{
+			Name:        name,
+			DetailsURL:  detailsURL,
+			Status:      github.String(checkStatus),
+			Conclusion:  conclusion,
+			CompletedAt: completedAt,
+			Output:      output,
+		})
+		return errors.Wrapf(err, `can't update check run for project "%s"`, projectName)
+	}
+	checkRun, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, github.CreateCheckRunOptions{
+		Name:        name,
+		HeadSHA:     s.env.Sha(),
+		DetailsURL:  detailsURL,
+		Status:      github.String(checkStatus),
+		Conclusion:  conclusion,
+		CompletedAt: completedAt,
+		Output:      output,
+	})
+	if err != nil {
+		return errors.Wrapf(err, `can't create check run for project "%s"`, projectName)
+	}
+	s.mu.Lock()
+	s.checkRunIDs[projectName] = checkRun.GetID()
+	s.mu.Unlock()
+	return nil
+}
+
+// checkRunStatusOf maps a state to a status of a check run and its conclusion, nil if not completed
+func checkRunStatusOf(state core.CommitState) (string, *string) {
+	switch state {
+	case core.CommitStatePending:
+		return "in_progress", nil
+	case core.CommitStateSuccess:
+		return "completed", github.String("success")
+	case core.CommitStateNeutral:
+		return "completed", github.String("neutral")
+	default:
+		return "completed", github.String("failure")
+	}
+}
+
+// get the number of the pull request being built, from a ref like "refs/pull/123/merge",
+// zero if not built for a pull request
+func (s *gitHubActionGateway) PullRequestNumber() int {
+	return pullRequestNumberOf(s.env.Ref())
+}
+
+func pullRequestNumberOf(ref string) int {
+	re := regexp.MustCompile(`^refs/pull/(\d+)/`)
+	match := re.FindStringSubmatch(ref)
+	if len(match) == 0 {
+		return 0
+	}
+	number, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// get links to the latest builds of given projects, from runs dispatched on the branch of the pull
+// request, up to LookbackRuns. With a workflow file of each project, the latest run of it is the
+// latest build, otherwise a run is of a project if its name is named after it,
+// e.g., "Build app (<correlation ID>)". Runs of repository dispatch events are on the default branch,
+// they can't be told apart from builds of other pull requests, so they are never linked.
+func (s *gitHubActionGateway) LatestBuildURLs(ctx context.Context, projectNames []string) (map[string]string, error) {
+	urls := make(map[string]string, len(projectNames))
+	branch := s.env.HeadRef()
+	if len(projectNames) == 0 || branch == "" || s.config.Dispatch != GitHubWorkflowDispatch {
+		return urls, nil
+	}
+	branchQuery := "&branch=" + url.QueryEscape(branch)
+	if strings.Contains(s.config.DispatchWorkflow, projectPlaceholder) {
+		for _, projectName := range projectNames {
+			workflowFile := workflowFileFor(s.config.DispatchWorkflow, projectName)
+			runsURL := s.dispatchedRunsURLFor(workflowFile) + branchQuery
+			err := s.listRuns(ctx, runsURL, func(run *workflowRunWithTitle) bool {
+				urls[projectName] = run.GetHTMLURL()
+				return false
+			})
+			if isNotFound(err) {
+				// the project has no workflow file, so it has no build
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, `can't list runs of workflow "%s"`, workflowFile)
+			}
+		}
+		return urls, nil
+	}
+
+	lookback := s.config.LookbackRuns
+	if lookback <= 0 {
+		lookback = defaultLookbackRuns
+	}
+	listed := 0
+	runsURL := s.dispatchedRunsURLFor(s.config.DispatchWorkflow) + branchQuery
+	err := s.listRuns(ctx, runsURL, func(run *workflowRunWithTitle) bool {
+		// runs are listed from the latest
+		for _, projectName := range projectNames {
+			if _, ok := urls[projectName]; ok != true && isRunOf(run, projectName) {
+				urls[projectName] = run.GetHTMLURL()
+			}
+		}
+		listed++
+		return len(urls) < len(projectNames) && listed < lookback
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "can't list runs of dispatched builds")
+	}
+	return urls, nil
+}
+
+// isRunOf checks if the name of a run is named after given project
+func isRunOf(run *workflowRunWithTitle, projectName string) bool {
+	return isNamedAfter(run.DisplayTitle, projectName) || isNamedAfter(run.Name, projectName)
+}
+
+// isNamedAfter checks if a name is named after given project, e.g., "app" or "Build app (abc123)",
+// but not after another project containing its name, e.g., "app-admin"
+func isNamedAfter(name string, projectName string) bool {
+	re := regexp.MustCompile(fmt.Sprintf(`(^|[^\w-])%s($|[^\w-])`, regexp.QuoteMeta(projectName)))
+	return re.MatchString(name)
+}
+
+func isNotFound(err error) bool {
+	resp, ok := errors.Cause(err).(*github.ErrorResponse)
+	return ok && resp.Response != nil && resp.Response.StatusCode == http.StatusNotFound
+}
+
+// marks the comment of affected projects, to be found and updated by later builds
+const affectedProjectsCommentMarker = "<!-- monorepo-toolkit:affected-projects -->"
+
+// post a report of affected projects on given pull request as a comment,
+// or update the comment posted by a previous build
+func (s *gitHubActionGateway) CommentAffectedProjects(
+	ctx context.Context,
+	number int,
+	report *core.AffectedProjectsReport,
+) (string, error) {
+	client := s.client(ctx)
+	owner, repo := s.env.Owner(), s.env.Repository()
+	body := affectedProjectsCommentOf(report)
+
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return "", errors.Wrapf(err, "can't list comments of pull request #%d", number)
+		}
+		for _, comment := range comments {
+			if strings.HasPrefix(comment.GetBody(), affectedProjectsCommentMarker) != true {
+				continue
+			}
+			edited, _, err := client.Issues.EditComment(ctx, owner, repo, comment.GetID(), &github.IssueComment{
+				Body: github.String(body),
+			})
+			if err != nil {
+				return "", errors.Wrapf(err, "can't update comment of pull request #%d, ID %d", number, comment.GetID())
+			}
+			return edited.GetHTMLURL(), nil
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	created, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "can't create comment of pull request #%d", number)
+	}
+	return created.GetHTMLURL(), nil
+}
+
+// maximum number of changed files listed for each project in a comment
+const maxCommentFiles = 3
+
+// affectedProjectsCommentOf renders a report of affected projects in markdown, starting with the marker
+func affectedProjectsCommentOf(report *core.AffectedProjectsReport) string {
+	var b strings.Builder
+	b.WriteString(affectedProjectsCommentMarker + "\n")
+	b.WriteString("### Affected projects\n\n")
+	if report.BaseCommit == "" {
+		fmt.Fprintf(&b, "No successful build found, all files at `%s` are considered changed.\n\n", shortHash(report.HeadCommit))
+	} else {
+		fmt.Fprintf(&b, "Changes from `%s` to `%s`.\n\n", shortHash(report.BaseCommit), shortHash(report.HeadCommit))
+	}
+	if len(report.Projects) == 0 {
+		b.WriteString("No project is affected by changes.\n")
+		return b.String()
+	}
+	b.WriteString("| Project | Path | Why | Build |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, p := range report.Projects {
+		build := "Not found"
+		if p.BuildURL != "" {
+			build = fmt.Sprintf("[Run](%s)", p.BuildURL)
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", p.Name, p.Path, affectReasonOf(p), build)
+	}
+	return b.String()
+}
+
+func affectReasonOf(p *core.AffectedProject) string {
+	if p.Reason == core.AffectedByDependency {
+		return fmt.Sprintf("Dependency: %s matches global trigger `%s`", codeList(p.Files), p.Trigger)
+	}
+	return fmt.Sprintf("Direct change: %s", codeList(p.Files))
+}
+
+// codeList formats files as inline code, listing at most maxCommentFiles of them
+func codeList(files []string) string {
+	n := len(files)
+	if n > maxCommentFiles {
+		n = maxCommentFiles
+	}
+	quoted := make([]string, n)
+	for i := 0; i < n; i++ {
+		quoted[i] = fmt.Sprintf("`%s`", files[i])
+	}
+	list := strings.Join(quoted, ", ")
+	if more := len(files) - n; more > 0 {
+		list += fmt.Sprintf(" and %d more", more)
+	}
+	return list
+}
+
+func shortHash(hash core.Hash) string {
+	if len(hash) > 7 {
+		return string(hash[:7])
+	}
+	return string(hash)
+}
+
+type failedStep struct {
+	job    string
+	number int64
+	name   string
+}
+
+func failedStepsOf(jobs []*github.WorkflowJob) []*failedStep {
+	steps := make([]*failedStep, 0)
+	for _, job := range jobs {
+		if job.GetConclusion() != "failure" {
+			continue
+		}
+		for _, step := range job.Steps {
+			if step.GetConclusion() == "failure" {
+				steps = append(steps, &failedStep{
+					job:    job.GetName(),
+					number: step.GetNumber(),
+					name:   step.GetName(),
+				})
+			}
+		}
+	}
+	return steps
+}
+
+// download gets a file from a pre-signed url, no authorization is needed
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// tailOfSteps reads the last lines of given steps from a logs archive,
+// each step log is stored in "<job name>/<step number>_<step name>.txt"
+func tailOfSteps(archive []byte, steps []*failedStep, lines int) ([]*core.LogExcerpt, error) {
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read logs archive")
+	}
+	excerpts := make([]*core.LogExcerpt, 0, len(steps))
+	for _, step := range steps {
+		prefix := fmt.Sprintf("%s/%d_", step.job, step.number)
+		for _, f := range r.File {
+			if strings.HasPrefix(f.Name, prefix) != true {
+				continue
+			}
+			content, err := readZipFile(f)
+			if err != nil {
+				return nil, errors.Wrapf(err, `can't read "%s" from logs archive`, f.Name)
+			}
+			excerpts = append(excerpts, &core.LogExcerpt{
+				Job:   step.job,
+				Step:  step.name,
+				Lines: tailOf(content, lines),
+			})
+			break
+		}
+	}
+	return excerpts, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+func tailOf(content []byte, lines int) []string {
+	if len(content) == 0 {
+		return []string{}
+	}
+	all := strings.Split(strings.TrimRight(string(content), "\r\n"), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	for i, line := range all {
+		all[i] = strings.TrimRight(line, "\r")
+	}
+	return all
+}
+
+// checks if a request failed with the given error can be retried, e.g., rate limits,
+// server errors or network timeouts
+// outputs how long the server asks to wait before retrying, zero if not specified
+func (s *gitHubActionGateway) IsRetryable(err error) (bool, time.Duration) {
+	switch e := errors.Cause(err).(type) {
+	case *github.RateLimitError:
+		// X-RateLimit-Reset header
+		return true, durationUntil(e.Rate.Reset.Time)
+	case *github.AbuseRateLimitError:
+		// Retry-After header
+		return true, e.GetRetryAfter()
+	case *github.ErrorResponse:
+		if e.Response == nil {
+			return false, 0
+		}
+		code := e.Response.StatusCode
+		if code >= 500 || code == http.StatusTooManyRequests {
+			return true, retryAfterOf(e.Response)
+		}
+		return false, 0
+	case net.Error:
+		return e.Timeout() || e.Temporary(), 0
+	default:
+		return false, 0
+	}
+}
+
+func retryAfterOf(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(v); err == nil {
+		return durationUntil(date)
+	}
+	return 0
+}
+
+func durationUntil(t time.Time) time.Duration {
+	d := time.Until(t)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -1,18 +1,28 @@
 package pipeline
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v32/github"
+	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/whatthefar/monorepo-toolkit/pkg/core"
+	mock_core "github.com/whatthefar/monorepo-toolkit/pkg/core/mock"
 	mock_pipeline "github.com/whatthefar/monorepo-toolkit/pkg/pipeline/mock"
-	"github.com/whatthefar/monorepo-toolkit/pkg/utils"
 	gitfixture "github.com/whatthefar/monorepo-toolkit/test/git-fixtures"
 )
 
@@ -79,9 +89,12 @@ func TestNewGitHubActionGateway(t *testing.T) {
 
 	env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
 
-	gw := NewGitHubActionGateway(env)
+	gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{})
 
 	assert.Implements(t, (*core.PipelineGateway)(nil), gw)
+	assert.Implements(t, (*core.BuildLogGateway)(nil), gw)
+	assert.Implements(t, (*core.CommitStatusGateway)(nil), gw)
+	assert.Implements(t, (*core.PullRequestGateway)(nil), gw)
 	assert.IsType(t, new(gitHubActionGateway), gw)
 
 	ghImpl, ok := gw.(*gitHubActionGateway)
@@ -108,7 +121,11 @@ func TestGitHubActionGateway_LastSuccesfulCommit(t *testing.T) {
 
 		repo := gitfixture.PipelineRepository()
 
-		gw := NewGitHubActionGateway(env)
+		// every commit of the fixture is on master
+		git := mock_core.NewMockGitGateway(ctrl)
+		git.EXPECT().IsAncestor(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+		gw := NewGitHubActionGateway(env, git, GitHubActionConfig{})
 
 		cases := []*struct {
 			workflowID string
@@ -140,6 +157,7 @@ func TestGitHubActionGateway_LastSuccesfulCommit(t *testing.T) {
 				env.EXPECT().Repository().Return(repo.Repository())
 				env.EXPECT().Branch().Return("master")
 				env.EXPECT().Ref().Return("refs/origin/master")
+				env.EXPECT().Sha().Return("ed5434c198b1721d5c83f3f39b6eea967c16f095")
 				got, err := gw.LastSuccessfulCommit(ctx, workflowID)
 
 				Convey("Then it should return commit hash with no error", func() {
@@ -152,6 +170,88 @@ func TestGitHubActionGateway_LastSuccesfulCommit(t *testing.T) {
 	})
 }
 
+func TestGitHubActionGateway_LastSuccesfulCommit_Paging(t *testing.T) {
+	Convey("Given a GitHubActionGateway with a fake API", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+		env.EXPECT().Token().Return("token").AnyTimes()
+		env.EXPECT().Owner().Return("owner").AnyTimes()
+		env.EXPECT().Repository().Return("repo").AnyTimes()
+		env.EXPECT().Branch().Return("master").AnyTimes()
+		env.EXPECT().Ref().Return("refs/heads/master").AnyTimes()
+		env.EXPECT().Sha().Return("head").AnyTimes()
+		git := mock_core.NewMockGitGateway(ctrl)
+
+		// successful runs from the latest, "forced" was force-pushed away
+		pages := map[string]string{
+			"1": `{"workflow_runs":[{"id":4,"run_number":4,"head_sha":"forced","conclusion":"success"},` +
+				`{"id":3,"run_number":3,"head_sha":"forced","conclusion":"success"}]}`,
+			"2": `{"workflow_runs":[{"id":2,"run_number":2,"head_sha":"base","conclusion":"success"}]}`,
+		}
+		var queries []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/actions/workflows/main.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.RawQuery)
+			page := r.URL.Query().Get("page")
+			if page == "" {
+				page = "1"
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+			}
+			fmt.Fprint(w, pages[page])
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		baseURL, err := url.Parse(server.URL + "/")
+		So(err, ShouldBeNil)
+		gw := &gitHubActionGateway{
+			env:     env,
+			git:     git,
+			config:  GitHubActionConfig{SuccessEvent: "push"},
+			baseURL: baseURL,
+		}
+
+		Convey("When calls LastSuccessfulCommit", func() {
+			git.EXPECT().IsAncestor(gomock.Any(), core.Hash("forced"), core.Hash("head")).Return(false, nil)
+			git.EXPECT().IsAncestor(gomock.Any(), core.Hash("base"), core.Hash("head")).Return(true, nil)
+			got, err := gw.LastSuccessfulCommit(ctx, "main.yml")
+
+			Convey("It should skip commits not ancestors of the current one, across pages", func() {
+				So(err, ShouldBeNil)
+				So(got, ShouldEqual, core.Hash("base"))
+				So(queries, ShouldResemble, []string{
+					"branch=master&event=push&per_page=100&status=success",
+					"branch=master&event=push&page=2&per_page=100&status=success",
+				})
+			})
+		})
+
+		Convey("When calls LastSuccessfulCommit, looking back fewer runs than before the ancestor", func() {
+			gw.config.LookbackRuns = 2
+			git.EXPECT().IsAncestor(gomock.Any(), core.Hash("forced"), core.Hash("head")).Return(false, nil)
+			got, err := gw.LastSuccessfulCommit(ctx, "main.yml")
+
+			Convey("It should find no commit, without listing the next page", func() {
+				So(err, ShouldBeNil)
+				So(got, ShouldEqual, core.Hash(""))
+				So(queries, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When calls LastSuccessfulCommit, and checking an ancestor fails", func() {
+			git.EXPECT().IsAncestor(gomock.Any(), core.Hash("forced"), core.Hash("head")).Return(false, errors.New("can't fetch"))
+			_, err := gw.LastSuccessfulCommit(ctx, "main.yml")
+
+			Convey("It should return the error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
 func TestGitHubActionGateway_CurrentCommit(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -163,7 +263,7 @@ func TestGitHubActionGateway_CurrentCommit(t *testing.T) {
 
 		env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
 
-		gw := NewGitHubActionGateway(env)
+		gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{})
 
 		cases := []*struct {
 			sha string
@@ -191,6 +291,354 @@ func TestGitHubActionGateway_CurrentCommit(t *testing.T) {
 	})
 }
 
+func TestGitHubActionGateway_BuildRequestFor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+	gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{})
+
+	cases := []*struct {
+		eventType   string
+		projectName string
+		want        *core.BuildRequest
+	}{
+		{
+			eventType:   "build",
+			projectName: "server",
+			want: &core.BuildRequest{
+				ProjectName: "server",
+				Event:       "build",
+				Payload:     `{"correlation_id":"abc123","job":"server"}`,
+			},
+		},
+		{
+			// no event type, defaults to "build-<project>"
+			eventType:   "",
+			projectName: `"quoted"`,
+			want: &core.BuildRequest{
+				ProjectName: `"quoted"`,
+				Event:       `build-"quoted"`,
+				Payload:     `{"correlation_id":"abc123","job":"\"quoted\""}`,
+			},
+		},
+	}
+
+	for i, v := range cases {
+		var (
+			eventType   = v.eventType
+			projectName = v.projectName
+			want        = v.want
+		)
+		t.Run(fmt.Sprintf("Case %d, calls buildRequestFor", i+1), func(t *testing.T) {
+			env.EXPECT().EventType().Return(eventType)
+			got, err := gw.(*gitHubActionGateway).buildRequestFor(projectName, "abc123")
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestGitHubActionGateway_BuildRequestFor_WorkflowDispatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+	env.EXPECT().Ref().Return("refs/heads/master").AnyTimes()
+	env.EXPECT().HeadRef().Return("").AnyTimes()
+	gw := &gitHubActionGateway{env: env, config: GitHubActionConfig{
+		Dispatch:         GitHubWorkflowDispatch,
+		DispatchWorkflow: "build-{project}.yml",
+		DispatchInputs:   map[string]interface{}{"environment": "staging", "debug": true, "replicas": 2},
+	}}
+
+	t.Run("calls buildRequestFor", func(t *testing.T) {
+		got, err := gw.buildRequestFor("server", "abc123")
+		assert.NoError(t, err)
+		// all inputs are sent as strings
+		assert.Equal(t, &core.BuildRequest{
+			ProjectName: "server",
+			Event:       "build-server.yml",
+			Payload:     `{"inputs":{"correlation_id":"abc123","debug":"true","environment":"staging","project":"server","replicas":"2"},"ref":"refs/heads/master"}`,
+		}, got)
+	})
+
+	t.Run("calls buildRequestFor for a pull request", func(t *testing.T) {
+		prEnv := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+		prEnv.EXPECT().HeadRef().Return("feature").AnyTimes()
+		gw := &gitHubActionGateway{env: prEnv, config: gw.config}
+		got, err := gw.buildRequestFor("server", "abc123")
+		assert.NoError(t, err)
+		// dispatched on the branch of the pull request
+		assert.Contains(t, got.Payload, `"ref":"feature"`)
+	})
+
+	t.Run("calls BuildRequestFor twice", func(t *testing.T) {
+		first, err := gw.BuildRequestFor("server")
+		assert.NoError(t, err)
+		second, err := gw.BuildRequestFor("server")
+		assert.NoError(t, err)
+		// each request is identified by a new correlation ID
+		assert.NotEqual(t, first.Payload, second.Payload)
+	})
+}
+
+func TestWorkflowFileFor(t *testing.T) {
+	assert.Equal(t, "build-app1.yml", workflowFileFor("build-{project}.yml", "app1"))
+	assert.Equal(t, "app1/app1.yml", workflowFileFor("{project}/{project}.yml", "app1"))
+	assert.Equal(t, "build.yml", workflowFileFor("build.yml", "app1"))
+}
+
+func TestNewCorrelationID(t *testing.T) {
+	id := newCorrelationID()
+	assert.Len(t, id, 32)
+	assert.NotEqual(t, id, newCorrelationID())
+}
+
+func TestGitHubActionGateway_findDispatchedRun(t *testing.T) {
+	Convey("Given a GitHubActionGateway with a fake API", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+		env.EXPECT().Token().Return("token").AnyTimes()
+		env.EXPECT().Owner().Return("owner").AnyTimes()
+		env.EXPECT().Repository().Return("repo").AnyTimes()
+
+		dispatchedAt := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+		createdAt := dispatchedAt.Add(time.Second).Format(time.RFC3339)
+		// runs are listed from the latest, the one of another build first
+		pages := map[string]string{
+			"1": `{"workflow_runs":[{"id":3,"created_at":"` + createdAt + `","display_title":"Build app-admin (other)"}]}`,
+			"2": `{"workflow_runs":[{"id":2,"created_at":"` + createdAt + `","display_title":"Build app (abc123)"}]}`,
+		}
+		var requests []string
+		jobRequests := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RawQuery)
+			page := r.URL.Query().Get("page")
+			if page == "1" {
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+			}
+			fmt.Fprint(w, pages[page])
+		})
+		mux.HandleFunc("/repos/owner/repo/actions/runs/3/jobs", func(w http.ResponseWriter, r *http.Request) {
+			jobRequests++
+			fmt.Fprint(w, `{"jobs":[{"name":"app-admin","steps":[{"name":"Set up job"}]}]}`)
+		})
+		// jobs are listed page by page, the step with the ID is of a job on the second page
+		mux.HandleFunc("/repos/owner/repo/actions/runs/2/jobs", func(w http.ResponseWriter, r *http.Request) {
+			jobRequests++
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+				fmt.Fprint(w, `{"jobs":[{"name":"lint","steps":[{"name":"Set up job"}]}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"jobs":[{"name":"app","steps":[{"name":"Correlation ID def456"}]}]}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		baseURL, err := url.Parse(server.URL + "/")
+		So(err, ShouldBeNil)
+		gw := &gitHubActionGateway{
+			env:     env,
+			config:  GitHubActionConfig{DispatchWait: 100 * time.Millisecond},
+			baseURL: baseURL,
+		}
+		runsURL := "repos/owner/repo/actions/runs?event=repository_dispatch"
+
+		Convey("When finds a run with a correlation ID on the second page", func() {
+			id, err := gw.findDispatchedRun(ctx, runsURL, "abc123", dispatchedAt)
+
+			Convey("It should list every page, and return the ID of the run without listing jobs", func() {
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, 2)
+				So(requests, ShouldResemble, []string{
+					"event=repository_dispatch&per_page=100&page=1",
+					"event=repository_dispatch&per_page=100&page=2",
+				})
+				So(jobRequests, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When finds a run with a correlation ID in the name of a step", func() {
+			id, err := gw.findDispatchedRun(ctx, runsURL, "def456", dispatchedAt)
+
+			Convey("It should return the ID of the run, after matching names of all runs and listing every page of jobs", func() {
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, 2)
+				So(len(requests), ShouldEqual, 2)
+				So(jobRequests, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When looks for a correlation ID of no run twice", func() {
+			checked := make(map[int64]bool)
+			since := dispatchedAt.Add(-dispatchClockSkew)
+			_, found, err := gw.findRunByCorrelationID(ctx, runsURL, "ghi789", since, checked)
+			So(err, ShouldBeNil)
+			So(found, ShouldBeFalse)
+			_, found, err = gw.findRunByCorrelationID(ctx, runsURL, "ghi789", since, checked)
+
+			Convey("It should list jobs of each run only once, since their steps have started", func() {
+				So(err, ShouldBeNil)
+				So(found, ShouldBeFalse)
+				So(jobRequests, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When finds a run with a correlation ID of no run", func() {
+			pages["2"] = `{"workflow_runs":[]}`
+			_, err := gw.findDispatchedRun(ctx, runsURL, "ghi789", dispatchedAt)
+
+			Convey("It should fail once the wait is over", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, `no run with correlation ID "ghi789" is found within 100ms`)
+			})
+		})
+
+		Convey("When finds a run dispatched after every listed run", func() {
+			_, err := gw.findDispatchedRun(ctx, runsURL, "abc123", dispatchedAt.Add(time.Hour))
+
+			Convey("It should stop listing at runs created before the dispatch", func() {
+				So(err, ShouldNotBeNil)
+				for _, query := range requests {
+					So(query, ShouldEndWith, "page=1")
+				}
+			})
+		})
+	})
+}
+
+func TestHasStepNamed(t *testing.T) {
+	jobs := []*github.WorkflowJob{
+		{
+			Name: github.String("build"),
+			Steps: []*github.TaskStep{
+				{Name: github.String("Set up job")},
+				{Name: github.String("Correlation ID abc123")},
+			},
+		},
+	}
+	assert.True(t, hasStepNamed(jobs, "abc123"))
+	assert.False(t, hasStepNamed(jobs, "def456"))
+	assert.False(t, hasStepNamed(nil, "abc123"))
+}
+
+func TestHasStepsListed(t *testing.T) {
+	started := &github.WorkflowJob{Steps: []*github.TaskStep{{Name: github.String("Set up job")}}}
+	queued := &github.WorkflowJob{}
+	assert.True(t, hasStepsListed([]*github.WorkflowJob{started}))
+	assert.True(t, hasStepsListed([]*github.WorkflowJob{queued, started}))
+	assert.False(t, hasStepsListed([]*github.WorkflowJob{queued}))
+	assert.False(t, hasStepsListed(nil))
+}
+
+func TestTailOfSteps(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"1_build.txt":            "whole job logs\n",
+		"build/1_Set up job.txt": "set up\n",
+		"build/3_Run tests.txt":  "=== RUN TestApp\r\n--- FAIL: TestApp\r\nFAIL\r\n",
+		"lint/2_Run lint.txt":    "",
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+
+	jobs := []*github.WorkflowJob{
+		{
+			Name:       github.String("build"),
+			Conclusion: github.String("failure"),
+			Steps: []*github.TaskStep{
+				{Name: github.String("Set up job"), Number: github.Int64(1), Conclusion: github.String("success")},
+				{Name: github.String("Run tests"), Number: github.Int64(3), Conclusion: github.String("failure")},
+			},
+		},
+		{
+			Name:       github.String("lint"),
+			Conclusion: github.String("failure"),
+			Steps: []*github.TaskStep{
+				{Name: github.String("Run lint"), Number: github.Int64(2), Conclusion: github.String("failure")},
+			},
+		},
+		{
+			Name:       github.String("deploy"),
+			Conclusion: github.String("skipped"),
+		},
+	}
+
+	got, err := tailOfSteps(buf.Bytes(), failedStepsOf(jobs), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.LogExcerpt{
+		{Job: "build", Step: "Run tests", Lines: []string{"--- FAIL: TestApp", "FAIL"}},
+		{Job: "lint", Step: "Run lint", Lines: []string{}},
+	}, got)
+}
+
+func TestGitHubActionGateway_TriggerBuild_FakeAPI(t *testing.T) {
+	Convey("Given a GitHubActionGateway with a fake API", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+		env.EXPECT().Token().Return("token").AnyTimes()
+		env.EXPECT().Owner().Return("owner").AnyTimes()
+		env.EXPECT().Repository().Return("repo").AnyTimes()
+		env.EXPECT().EventType().Return("build")
+
+		// the dispatched run shows the correlation ID of the payload in its name
+		var payload struct {
+			EventType     string            `json:"event_type"`
+			ClientPayload map[string]string `json:"client_payload"`
+		}
+		var runsQuery string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/dispatches", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.WriteHeader(http.StatusNoContent)
+		})
+		mux.HandleFunc("/repos/owner/repo/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+			runsQuery = r.URL.RawQuery
+			fmt.Fprintf(
+				w,
+				`{"workflow_runs":[{"id":42,"created_at":"%s","display_title":"Build %s (%s)"}]}`,
+				time.Now().UTC().Format(time.RFC3339),
+				payload.ClientPayload["job"],
+				payload.ClientPayload["correlation_id"],
+			)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		baseURL, err := url.Parse(server.URL + "/")
+		So(err, ShouldBeNil)
+		gw := &gitHubActionGateway{env: env, baseURL: baseURL}
+
+		Convey("When calls TriggerBuild", func() {
+			got, err := gw.TriggerBuild(ctx, "server")
+
+			Convey("It should dispatch an event, and return the ID of the run showing its correlation ID", func() {
+				So(err, ShouldBeNil)
+				So(payload.EventType, ShouldEqual, "build")
+				So(payload.ClientPayload["job"], ShouldEqual, "server")
+				So(runsQuery, ShouldStartWith, "event=repository_dispatch&")
+				So(got, ShouldNotBeNil)
+				So(*got, ShouldEqual, "42")
+			})
+		})
+	})
+}
+
 func TestGitHubActionGateway_TriggerBuild(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -208,7 +656,7 @@ func TestGitHubActionGateway_TriggerBuild(t *testing.T) {
 
 		repo := gitfixture.PipelineRepository()
 
-		gw := NewGitHubActionGateway(env)
+		gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{})
 
 		cases := []*struct {
 			eventType      string
@@ -217,16 +665,18 @@ func TestGitHubActionGateway_TriggerBuild(t *testing.T) {
 			shouldError    bool
 		}{
 			{
+				// the workflow of the fixture does not show the correlation ID, so its run is not found
 				eventType:      "build",
 				projectName:    "server",
-				shouldReturnID: true,
-				shouldError:    false,
+				shouldReturnID: false,
+				shouldError:    true,
 			},
 			{
+				// no run is created, so no run is found in time
 				eventType:      "nop",
 				projectName:    "server",
 				shouldReturnID: false,
-				shouldError:    false,
+				shouldError:    true,
 			},
 		}
 
@@ -267,6 +717,343 @@ func TestGitHubActionGateway_TriggerBuild(t *testing.T) {
 	})
 }
 
+func TestGitHubActionGateway_BuildURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+	env.EXPECT().Owner().Return("WhatTheFar").AnyTimes()
+	env.EXPECT().Repository().Return("monorepo-toolkit").AnyTimes()
+	serverURL := env.EXPECT().ServerURL().Return("https://github.com")
+	gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{})
+
+	got := gw.BuildURL("145647641")
+	assert.Equal(t, "https://github.com/WhatTheFar/monorepo-toolkit/actions/runs/145647641", got)
+
+	// on GitHub Enterprise Server
+	env.EXPECT().ServerURL().Return("https://github.example.com").After(serverURL)
+	got = gw.BuildURL("145647641")
+	assert.Equal(t, "https://github.example.com/WhatTheFar/monorepo-toolkit/actions/runs/145647641", got)
+}
+
+func TestBuildStateOf(t *testing.T) {
+	cases := []*struct {
+		status     string
+		conclusion string
+		state      core.BuildState
+	}{
+		{status: "queued", conclusion: "", state: core.BuildStateQueued},
+		{status: "in_progress", conclusion: "", state: core.BuildStateRunning},
+		{status: "completed", conclusion: "success", state: core.BuildStateSuccess},
+		{status: "completed", conclusion: "failure", state: core.BuildStateFailed},
+		{status: "completed", conclusion: "cancelled", state: core.BuildStateCancelled},
+		{status: "completed", conclusion: "skipped", state: core.BuildStateSkipped},
+		{status: "completed", conclusion: "timed_out", state: core.BuildStateTimedOut},
+		{status: "completed", conclusion: "new_conclusion", state: core.BuildStateUnknown},
+		{status: "new_status", conclusion: "", state: core.BuildStateUnknown},
+	}
+
+	for i, v := range cases {
+		var (
+			status     = v.status
+			conclusion = v.conclusion
+			want       = v.state
+		)
+		t.Run(fmt.Sprintf("Case %d, calls buildStateOf", i+1), func(t *testing.T) {
+			got := buildStateOf(status, conclusion)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestRepoStatusOf(t *testing.T) {
+	cases := []*struct {
+		status *core.ProjectStatus
+		want   *github.RepoStatus
+	}{
+		{
+			status: &core.ProjectStatus{
+				State:       core.CommitStatePending,
+				Description: "Build is running",
+				TargetURL:   "https://github.com/owner/repo/actions/runs/123",
+			},
+			want: &github.RepoStatus{
+				State:       github.String("pending"),
+				Description: github.String("Build is running"),
+				Context:     github.String("monorepo/app1"),
+				TargetURL:   github.String("https://github.com/owner/repo/actions/runs/123"),
+			},
+		},
+		{
+			// commit statuses have no neutral state
+			status: &core.ProjectStatus{State: core.CommitStateNeutral, Description: "Not affected by changes"},
+			want: &github.RepoStatus{
+				State:       github.String("success"),
+				Description: github.String("Not affected by changes"),
+				Context:     github.String("monorepo/app1"),
+			},
+		},
+		{
+			status: &core.ProjectStatus{State: core.CommitStateFailure, Description: strings.Repeat("a", 200)},
+			want: &github.RepoStatus{
+				State:       github.String("failure"),
+				Description: github.String(strings.Repeat("a", 139) + "…"),
+				Context:     github.String("monorepo/app1"),
+			},
+		},
+	}
+
+	for i, v := range cases {
+		var (
+			status = v.status
+			want   = v.want
+		)
+		t.Run(fmt.Sprintf("Case %d, calls repoStatusOf", i+1), func(t *testing.T) {
+			got := repoStatusOf("app1", status)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestCheckRunStatusOf(t *testing.T) {
+	cases := []*struct {
+		state      core.CommitState
+		status     string
+		conclusion *string
+	}{
+		{state: core.CommitStatePending, status: "in_progress", conclusion: nil},
+		{state: core.CommitStateSuccess, status: "completed", conclusion: github.String("success")},
+		{state: core.CommitStateFailure, status: "completed", conclusion: github.String("failure")},
+		{state: core.CommitStateNeutral, status: "completed", conclusion: github.String("neutral")},
+	}
+
+	for i, v := range cases {
+		var (
+			state      = v.state
+			status     = v.status
+			conclusion = v.conclusion
+		)
+		t.Run(fmt.Sprintf("Case %d, calls checkRunStatusOf", i+1), func(t *testing.T) {
+			gotStatus, gotConclusion := checkRunStatusOf(state)
+			assert.Equal(t, status, gotStatus)
+			assert.Equal(t, conclusion, gotConclusion)
+		})
+	}
+}
+
+func TestGitHubActionGateway_SetProjectStatus_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no request is sent, so no env is read
+	env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+	gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{}).(core.CommitStatusGateway)
+
+	err := gw.SetProjectStatus(context.Background(), "app1", &core.ProjectStatus{State: core.CommitStatePending})
+	assert.NoError(t, err)
+}
+
+func TestPullRequestNumberOf(t *testing.T) {
+	cases := []*struct {
+		ref  string
+		want int
+	}{
+		{ref: "refs/pull/123/merge", want: 123},
+		{ref: "refs/pull/7/head", want: 7},
+		{ref: "refs/heads/master", want: 0},
+		{ref: "refs/tags/v1.0.0", want: 0},
+	}
+
+	for i, v := range cases {
+		var (
+			ref  = v.ref
+			want = v.want
+		)
+		t.Run(fmt.Sprintf("Case %d, calls pullRequestNumberOf", i+1), func(t *testing.T) {
+			assert.Equal(t, want, pullRequestNumberOf(ref))
+		})
+	}
+}
+
+func TestIsNamedAfter(t *testing.T) {
+	cases := []*struct {
+		name        string
+		projectName string
+		want        bool
+	}{
+		{name: "app", projectName: "app", want: true},
+		{name: "Build app (abc123)", projectName: "app", want: true},
+		{name: "Build app-admin (abc123)", projectName: "app", want: false},
+		{name: "webapp", projectName: "app", want: false},
+		{name: "app.v2", projectName: "app.v2", want: true},
+		{name: "appxv2", projectName: "app.v2", want: false},
+	}
+
+	for i, v := range cases {
+		var (
+			name        = v.name
+			projectName = v.projectName
+			want        = v.want
+		)
+		t.Run(fmt.Sprintf("Case %d, calls isNamedAfter", i+1), func(t *testing.T) {
+			assert.Equal(t, want, isNamedAfter(name, projectName))
+		})
+	}
+}
+
+func TestGitHubActionGateway_LatestBuildURLs(t *testing.T) {
+	Convey("Given a GitHubActionGateway with a fake API, built for a pull request", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
+		env.EXPECT().Token().Return("token").AnyTimes()
+		env.EXPECT().Owner().Return("owner").AnyTimes()
+		env.EXPECT().Repository().Return("repo").AnyTimes()
+		env.EXPECT().HeadRef().Return("feature").AnyTimes()
+
+		var requests []string
+		mux := http.NewServeMux()
+		// runs are listed from the latest, the fake API ignores the branch filter
+		mux.HandleFunc("/repos/owner/repo/actions/workflows/build.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RawQuery)
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+				fmt.Fprint(w, `{"workflow_runs":[
+					{"id":3,"html_url":"https://github.com/owner/repo/actions/runs/3","display_title":"Build app-admin (c3)"},
+					{"id":2,"html_url":"https://github.com/owner/repo/actions/runs/2","display_title":"Build app (c2)"}
+				]}`)
+				return
+			}
+			fmt.Fprint(w, `{"workflow_runs":[
+				{"id":1,"html_url":"https://github.com/owner/repo/actions/runs/1","display_title":"Build lib (c1)"}
+			]}`)
+		})
+		mux.HandleFunc("/repos/owner/repo/actions/workflows/build-app.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RawQuery)
+			fmt.Fprint(w, `{"workflow_runs":[
+				{"id":5,"html_url":"https://github.com/owner/repo/actions/runs/5"},
+				{"id":4,"html_url":"https://github.com/owner/repo/actions/runs/4"}
+			]}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		baseURL, err := url.Parse(server.URL + "/")
+		So(err, ShouldBeNil)
+		gw := &gitHubActionGateway{
+			env:     env,
+			baseURL: baseURL,
+			config:  GitHubActionConfig{Dispatch: GitHubWorkflowDispatch, DispatchWorkflow: "build.yml"},
+		}
+
+		Convey("When calls LatestBuildURLs, with a workflow file of all projects", func() {
+			urls, err := gw.LatestBuildURLs(ctx, []string{"app", "lib", "web"})
+
+			Convey("It should list every page of the branch, and link the latest run named after each project", func() {
+				So(err, ShouldBeNil)
+				So(urls, ShouldResemble, map[string]string{
+					"app": "https://github.com/owner/repo/actions/runs/2",
+					"lib": "https://github.com/owner/repo/actions/runs/1",
+				})
+				So(requests, ShouldResemble, []string{
+					"event=workflow_dispatch&branch=feature&per_page=100&page=1",
+					"event=workflow_dispatch&branch=feature&per_page=100&page=2",
+				})
+			})
+		})
+
+		Convey("When calls LatestBuildURLs, looking back fewer runs", func() {
+			gw.config.LookbackRuns = 2
+			urls, err := gw.LatestBuildURLs(ctx, []string{"app", "lib"})
+
+			Convey("It should stop listing once the lookback is reached", func() {
+				So(err, ShouldBeNil)
+				So(urls, ShouldResemble, map[string]string{"app": "https://github.com/owner/repo/actions/runs/2"})
+				So(len(requests), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When calls LatestBuildURLs, with a workflow file of each project", func() {
+			gw.config.DispatchWorkflow = "build-{project}.yml"
+			urls, err := gw.LatestBuildURLs(ctx, []string{"app", "lib"})
+
+			Convey("It should link the latest run of the workflow of each project, skipping missing workflows", func() {
+				So(err, ShouldBeNil)
+				So(urls, ShouldResemble, map[string]string{"app": "https://github.com/owner/repo/actions/runs/5"})
+				So(requests, ShouldResemble, []string{"event=workflow_dispatch&branch=feature&per_page=100&page=1"})
+			})
+		})
+
+		Convey("When calls LatestBuildURLs, with repository dispatch", func() {
+			gw.config = GitHubActionConfig{}
+			urls, err := gw.LatestBuildURLs(ctx, []string{"app", "lib"})
+
+			Convey("It should link no run, since runs are not on the branch of the pull request", func() {
+				So(err, ShouldBeNil)
+				So(urls, ShouldBeEmpty)
+				So(requests, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestAffectedProjectsCommentOf(t *testing.T) {
+	Convey("Given a report of affected projects", t, func() {
+		report := &core.AffectedProjectsReport{
+			BaseCommit: "1234567890",
+			HeadCommit: "abcdefghij",
+			Projects: []*core.AffectedProject{
+				{
+					Name:     "app1",
+					Path:     "services/app1",
+					Reason:   core.AffectedByChange,
+					Files:    []string{"services/app1/a.go", "services/app1/b.go", "services/app1/c.go", "services/app1/d.go"},
+					BuildURL: "https://github.com/owner/repo/actions/runs/1",
+				},
+				{
+					Name:    "app2",
+					Path:    "services/app2",
+					Reason:  core.AffectedByDependency,
+					Files:   []string{"go.mod"},
+					Trigger: "go.mod",
+				},
+			},
+		}
+
+		Convey("When renders a comment", func() {
+			got := affectedProjectsCommentOf(report)
+
+			Convey("It should list why each project is affected, and links to builds", func() {
+				want := `<!-- monorepo-toolkit:affected-projects -->
+### Affected projects
+
+Changes from ` + "`1234567`" + ` to ` + "`abcdefg`" + `.
+
+| Project | Path | Why | Build |
+| --- | --- | --- | --- |
+| app1 | ` + "`services/app1`" + ` | Direct change: ` + "`services/app1/a.go`, `services/app1/b.go`, `services/app1/c.go`" + ` and 1 more | [Run](https://github.com/owner/repo/actions/runs/1) |
+| app2 | ` + "`services/app2`" + ` | Dependency: ` + "`go.mod`" + ` matches global trigger ` + "`go.mod`" + ` | Not found |
+`
+				So(got, ShouldEqual, want)
+			})
+		})
+
+		Convey("When renders a comment with no affected projects", func() {
+			report.BaseCommit = ""
+			report.Projects = nil
+			got := affectedProjectsCommentOf(report)
+
+			Convey("It should tell no project is affected", func() {
+				So(got, ShouldStartWith, affectedProjectsCommentMarker)
+				So(got, ShouldContainSubstring, "No successful build found, all files at `abcdefg` are considered changed.")
+				So(got, ShouldEndWith, "No project is affected by changes.\n")
+			})
+		})
+	})
+}
+
 func TestGitHubActionGateway_BuildStatus(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -284,22 +1071,22 @@ func TestGitHubActionGateway_BuildStatus(t *testing.T) {
 
 		repo := gitfixture.PipelineRepository()
 
-		gw := NewGitHubActionGateway(env)
+		gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{})
 
 		cases := []*struct {
-			runID  string
-			status *string
+			runID string
+			state core.BuildState
 		}{
 			// buld-failed.yml, second run. SHA: 7163c77dbfb2ed57eab8de7eacc528081eb702c1
-			{runID: "145647641", status: utils.StrAddr("success")},
+			{runID: "145647641", state: core.BuildStateSuccess},
 			// buld-failed.yml, first run.	SHA: 6e2d4b32f1dae634a08ebe97131276d76e1b11b9
-			{runID: "145647981", status: utils.StrAddr("failed")},
+			{runID: "145647981", state: core.BuildStateFailed},
 		}
 
 		for i, v := range cases {
 			var (
 				runID = v.runID
-				want  = v.status
+				want  = v.state
 			)
 
 			Convey(fmt.Sprintf(
@@ -310,15 +1097,16 @@ func TestGitHubActionGateway_BuildStatus(t *testing.T) {
 				env.EXPECT().Owner().Return(repo.Owner())
 				env.EXPECT().Repository().Return(repo.Repository())
 				var (
-					got *string
+					got *core.BuildStatus
 					err error
 				)
 				got, err = gw.BuildStatus(ctx, runID)
 
-				Convey(fmt.Sprintf("Then it should return status \"%v\"", want), func() {
+				Convey(fmt.Sprintf("Then it should return state \"%v\"", want), func() {
 					So(err, ShouldBeNil)
 					So(got, ShouldNotBeNil)
-					So(got, ShouldResemble, want)
+					So(got.State, ShouldEqual, want)
+					So(got.WebURL, ShouldNotBeEmpty)
 				})
 			})
 		}
@@ -341,14 +1129,13 @@ func TestGitHubActionGateway_KillBulid(t *testing.T) {
 		env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
 		env.EXPECT().Token().Return(token).AnyTimes()
 
-		gw := NewGitHubActionGateway(env)
+		gw := &gitHubActionGateway{env: env}
 
-		Convey("Given a build was triggered via TriggerBuild, on git-fixture-pipeline", func() {
+		Convey("Given a build was dispatched, on git-fixture-pipeline", func() {
 			env.EXPECT().Owner().Return(repo.Owner()).MinTimes(1)
 			env.EXPECT().Repository().Return(repo.Repository()).MinTimes(1)
-			env.EXPECT().EventType().Return("build-kill")
 
-			runID, err := gw.TriggerBuild(ctx, "server")
+			runID, err := dispatchRun(ctx, gw, "build-kill")
 
 			So(err, ShouldBeNil)
 			So(runID, ShouldNotBeNil)
@@ -362,7 +1149,7 @@ func TestGitHubActionGateway_KillBulid(t *testing.T) {
 				env := mock_pipeline.NewMockGitHubActionEnv(ctrl)
 				env.EXPECT().Token().Return(token)
 
-				gw := NewGitHubActionGateway(env)
+				gw := NewGitHubActionGateway(env, nil, GitHubActionConfig{})
 
 				Convey("When calls KillBuild with triggered run ID", func() {
 					env.EXPECT().Owner().Return(repo.Owner())
@@ -380,3 +1167,108 @@ func TestGitHubActionGateway_KillBulid(t *testing.T) {
 
 	})
 }
+
+// dispatchRun dispatches an event, and waits for the latest run created after it, since the
+// workflow of git-fixture-pipeline does not show correlation IDs for TriggerBuild
+func dispatchRun(ctx context.Context, gw *gitHubActionGateway, eventType string) (*string, error) {
+	owner, repo := gw.env.Owner(), gw.env.Repository()
+	since := time.Now().Add(-dispatchClockSkew)
+	_, _, err := gw.client(ctx).Repositories.Dispatch(ctx, owner, repo, github.DispatchRequestOptions{EventType: eventType})
+	if err != nil {
+		return nil, err
+	}
+	runsURL := fmt.Sprintf("repos/%s/%s/actions/runs?event=repository_dispatch", owner, repo)
+	for deadline := time.Now().Add(defaultDispatchWait); time.Now().Before(deadline); {
+		var runID *string
+		err := gw.listRuns(ctx, runsURL, func(run *workflowRunWithTitle) bool {
+			if run.GetCreatedAt().After(since) && run.GetStatus() != "completed" {
+				runID = github.String(fmt.Sprintf("%d", run.GetID()))
+			}
+			return false
+		})
+		if err != nil || runID != nil {
+			return runID, err
+		}
+		time.Sleep(dispatchPollInterval)
+	}
+	return nil, errors.Errorf(`no run of "%s" is found`, eventType)
+}
+
+func TestGitHubActionGateway_IsRetryable(t *testing.T) {
+	responseOf := func(code int, header http.Header) *http.Response {
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{StatusCode: code, Header: header}
+	}
+	retryAfter := 30 * time.Second
+
+	cases := []*struct {
+		err       error
+		retryable bool
+		wait      time.Duration
+	}{
+		{
+			err:       errors.Wrap(&github.ErrorResponse{Response: responseOf(502, nil)}, "can't dispatch event"),
+			retryable: true,
+			wait:      0,
+		},
+		{
+			err:       &github.ErrorResponse{Response: responseOf(503, http.Header{"Retry-After": {"10"}})},
+			retryable: true,
+			wait:      10 * time.Second,
+		},
+		{
+			err:       &github.ErrorResponse{Response: responseOf(404, nil)},
+			retryable: false,
+			wait:      0,
+		},
+		{
+			err:       &github.AbuseRateLimitError{Response: responseOf(403, nil), RetryAfter: &retryAfter},
+			retryable: true,
+			wait:      retryAfter,
+		},
+		{
+			// rate limit has already been reset
+			err:       &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: time.Now().Add(-time.Minute)}}},
+			retryable: true,
+			wait:      0,
+		},
+		{
+			err:       errors.Wrap(&url.Error{Op: "Get", URL: "https://api.github.com", Err: timeoutError{}}, "can't get a workflow run"),
+			retryable: true,
+			wait:      0,
+		},
+		{
+			err:       &dispatchedError{&github.ErrorResponse{Response: responseOf(502, nil)}},
+			retryable: false,
+			wait:      0,
+		},
+		{
+			err:       errors.New("invalid build ID"),
+			retryable: false,
+			wait:      0,
+		},
+	}
+
+	gw := &gitHubActionGateway{}
+	for i, v := range cases {
+		var (
+			err           = v.err
+			wantRetryable = v.retryable
+			wantWait      = v.wait
+		)
+		t.Run(fmt.Sprintf("Case %d, calls IsRetryable", i+1), func(t *testing.T) {
+			retryable, wait := gw.IsRetryable(err)
+
+			assert.Equal(t, wantRetryable, retryable)
+			assert.Equal(t, wantWait, wait)
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
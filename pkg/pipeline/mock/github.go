@@ -60,6 +60,20 @@ func (mr *MockGitHubActionEnvMockRecorder) EventType() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventType", reflect.TypeOf((*MockGitHubActionEnv)(nil).EventType))
 }
 
+// HeadRef mocks base method
+func (m *MockGitHubActionEnv) HeadRef() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeadRef")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HeadRef indicates an expected call of HeadRef
+func (mr *MockGitHubActionEnvMockRecorder) HeadRef() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadRef", reflect.TypeOf((*MockGitHubActionEnv)(nil).HeadRef))
+}
+
 // Owner mocks base method
 func (m *MockGitHubActionEnv) Owner() string {
 	m.ctrl.T.Helper()
@@ -102,6 +116,20 @@ func (mr *MockGitHubActionEnvMockRecorder) Repository() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repository", reflect.TypeOf((*MockGitHubActionEnv)(nil).Repository))
 }
 
+// ServerURL mocks base method
+func (m *MockGitHubActionEnv) ServerURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ServerURL indicates an expected call of ServerURL
+func (mr *MockGitHubActionEnvMockRecorder) ServerURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerURL", reflect.TypeOf((*MockGitHubActionEnv)(nil).ServerURL))
+}
+
 // Sha mocks base method
 func (m *MockGitHubActionEnv) Sha() string {
 	m.ctrl.T.Helper()